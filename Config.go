@@ -0,0 +1,39 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Config is a marker to be embedded in config structs to declare settings on the struct itself, so that a config
+// definition is self-contained. The settings are given in the `config` tag of the embedded field:
+//
+//	type MyConfig struct {
+//		config.Config `config:"prefix=MYAPP"`
+//		Port int      `config:"PORT"`
+//	}
+//
+// The supported setting is `prefix`, which is used as the Prefix when the ServiceConfig has none. A Prefix set on the
+// ServiceConfig always overrides the one declared on the struct.
+type Config struct{}
+
+var markerType = reflect.TypeOf(Config{})
+
+// withStructSettings returns sc with the settings declared by the Config marker of the struct pointed by obj applied.
+func (sc ServiceConfig) withStructSettings(obj interface{}) ServiceConfig {
+	t := reflect.Indirect(reflect.ValueOf(obj)).Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Type != markerType {
+			continue
+		}
+
+		for _, setting := range strings.Split(t.Field(i).Tag.Get("config"), ",") {
+			key, value, _ := strings.Cut(setting, "=")
+			if strings.TrimSpace(key) == "prefix" && sc.Prefix == "" {
+				sc.Prefix = value
+			}
+		}
+	}
+
+	return sc
+}
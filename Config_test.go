@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestConfig_prefix(t *testing.T) {
+	type TestConfig struct {
+		Config `config:"prefix=DECLARED"`
+		Port   int `config:"PORT"`
+	}
+
+	t.Setenv("DECLARED_PORT", "80")
+	t.Setenv("OVERRIDE_PORT", "443")
+
+	n := &TestConfig{}
+	err := ServiceConfig{ArraySeparator: " "}.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Port != 80 {
+		t.Fatalf("expected port from the declared prefix, received: %d", n.Port)
+	}
+
+	err = ServiceConfig{Prefix: "OVERRIDE", ArraySeparator: " "}.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Port != 443 {
+		t.Fatalf("expected port from the ServiceConfig prefix, received: %d", n.Port)
+	}
+}
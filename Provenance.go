@@ -0,0 +1,32 @@
+package config
+
+// Provenance maps the struct field name of every field tagged with `config` to where its value came from. The origin
+// is one of the Provenance constants, or the name of the Source that provided the value: its String method if it has
+// one, or its Go type otherwise. A Provenance only names origins, it never contains config values.
+type Provenance map[string]string
+
+const (
+	// ProvenanceEnv is the origin of values read from the environment.
+	ProvenanceEnv = "env"
+	// ProvenanceDefault is the origin of fields that were not configured and kept their prefilled, non-zero value.
+	ProvenanceDefault = "default"
+	// ProvenanceUnset is the origin of fields that were not configured and hold their zero value.
+	ProvenanceUnset = "unset"
+	// ProvenanceComputed is the origin of fields filled with the `compute` option.
+	ProvenanceComputed = "computed"
+)
+
+// ParseToWithProvenance parses configs into obj the same way as ParseTo, and returns where the value of every field
+// came from. It is meant for debugging layered configurations, e.g. to tell whether a value was read from the
+// environment, from one of the Sources, or left as the prefilled default.
+func (sc ServiceConfig) ParseToWithProvenance(obj interface{}) (Provenance, error) {
+	assertPointer(obj)
+
+	state := &parseState{provenance: make(Provenance)}
+	err := sc.parse(obj, state)
+	if err != nil {
+		return nil, err
+	}
+
+	return state.provenance, nil
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestServiceConfig_ParseToWithProvenance(t *testing.T) {
+	type TestConfig struct {
+		Port     int    `config:"PORT"`
+		Host     string `config:"HOST"`
+		Password string `config:"PASSWORD,secure"`
+		Timeout  int    `config:"TIMEOUT"`
+		Name     string `config:"NAME"`
+		URL      string `config:"-,compute=http://{HOST}:{PORT}"`
+	}
+
+	t.Setenv("PROVENANCE_HOST", "localhost")
+
+	sc := ServiceConfig{
+		Prefix:         "PROVENANCE",
+		ArraySeparator: " ",
+		Sources: []Source{
+			EnvSource{},
+			MapSource{"PROVENANCE_PORT": "80", "PROVENANCE_PASSWORD": "hunter2"},
+		},
+	}
+
+	provenance, err := sc.ParseToWithProvenance(&TestConfig{Timeout: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := Provenance{
+		"Port":     "config.MapSource",
+		"Host":     ProvenanceEnv,
+		"Password": "config.MapSource",
+		"Timeout":  ProvenanceDefault,
+		"Name":     ProvenanceUnset,
+		"URL":      ProvenanceComputed,
+	}
+	if !reflect.DeepEqual(expect, provenance) {
+		t.Fatalf("provenance is not the same with expectation, received: %v, expected: %v", provenance, expect)
+	}
+}
@@ -1,13 +1,23 @@
 package config
 
 import (
+	"context"
+	"database/sql"
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
+	"net"
 	"os"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // The ServiceConfig allows creators of a service to interact with environment variables easily.
@@ -19,274 +29,1701 @@ import (
 type ServiceConfig struct {
 	// The Prefix is added to all the config name that is supplied in getter functions
 	// such as the GetString or through the use struct tags.
+	//
+	// The Prefix may reference environment variables as $VAR or ${VAR}, expanded whenever a key is composed, e.g.
+	// "MYAPP_${REGION}" reads PORT from MYAPP_EU_PORT when REGION is "EU". Getters and ParseTo return an error when a
+	// referenced variable is not set or empty, rather than reading from a key such as MYAPP__PORT.
 	Prefix string
+	// KeySeparator joins the Prefix, the Environment and the config name into the key that is looked up, "_" when
+	// empty. For example, with Prefix "MYAPP" and KeySeparator "-", the config name PORT is read from MYAPP-PORT,
+	// and struct tags may declare dashed names such as `config:"MAX-CONNS"`. It is independent of ArraySeparator.
+	KeySeparator string
 	// The token to use to separate string in environment variables into array.
 	// Used by getters such as GetStringArray.
 	ArraySeparator string
+	// The Environment, when set, adds a higher priority lookup for every config name. For example, with Prefix "MYAPP"
+	// and Environment "production", the config name PORT is first looked up from "MYAPP_PRODUCTION_PORT", and only
+	// when that does not exist, from "MYAPP_PORT". The Environment is upper-cased when composing the name.
+	Environment string
+	// The Sources to read configs from, in order of precedence. The first Source that has a config wins. When nil,
+	// configs are read from the environment only. To combine the environment with other sources, include EnvSource
+	// at the desired position.
+	Sources []Source
+	// LookupFunc, when set, replaces os.LookupEnv wherever configs are read from the environment: by getters and
+	// ParseTo when Sources is nil, by an EnvSource in Sources, in expanding the Prefix, and by SetDefault to tell
+	// whether a config exists, e.g. to supply configs from a map in tests without implementing a Source. Values it
+	// returns are reported as coming from the environment. When nil, the process environment is read.
+	//
+	// Methods that list or modify the process environment itself, such as MatchingKeys, UnusedKeys, LoadEnvFile and
+	// the writes of SetDefault, do not use it.
+	LookupFunc func(key string) (string, bool)
+	// RequireAll makes every field parsed by ParseTo required, as if all of them were tagged with the `required`
+	// option. Fields with a default, see ParseTo, or computed with the `compute` option are never required.
+	RequireAll bool
+	// NoExtraKeys makes ParseTo return an ExtraKeysError when the environment holds variables that start with the
+	// Prefix but are not read by any field of the struct, see UnusedKeys, e.g. to reject typos and stale configs at
+	// startup. It is checked after all fields are parsed, and not by ParseGroup, which reads only part of the struct.
+	// Since only the process environment can be listed, ParseTo returns an error when NoExtraKeys is set along with
+	// Sources or LookupFunc, rather than checking keys that are not read.
+	NoExtraKeys bool
+	// ZeroMissing makes ParseTo set fields that are not configured, and have no default, to their zero value, instead
+	// of keeping the value they held before, so that parsing again into the same struct after a change in the
+	// environment resets the configs that were removed.
+	ZeroMissing bool
+	// ReadOnly forbids every method that modifies the process environment, such as LoadEnvFile and SetDefault, so
+	// that configs can only come from the real environment. Those methods return ErrReadOnly instead. Getters and
+	// ParseTo are not affected.
+	ReadOnly bool
+	// NumberGrouping, when set, is removed from integer and float values before they are parsed, so that operators
+	// may write large numbers such as "1,000,000" with NumberGrouping ",". Array values are split using
+	// ArraySeparator first, and the grouping is removed from every element afterwards, so NumberGrouping must differ
+	// from ArraySeparator: numeric array getters return an error when they are the same.
+	NumberGrouping string
+	// RejectLeadingZeros makes decimal integer values with a leading zero, such as "0755", an error instead of
+	// being parsed as 755, so that operators cannot mistake them for octal. A lone "0" is accepted. Values parsed
+	// with an explicit base, see GetIntArrayBase, are not affected.
+	RejectLeadingZeros bool
+	// AutoSeparators lists, in order of preference, the separators that array getters look for in a value when the
+	// separator is inferred with WithAutoSeparator or the `autosep` tag option. When empty, ",", ";" and " " are
+	// looked for, in this order.
+	AutoSeparators []string
+	// RequireArraySeparator makes array getters, and ParseTo for array fields, return ErrNoArraySeparator when
+	// ArraySeparator is empty, instead of splitting values into single characters as strings.Split does.
+	RequireArraySeparator bool
+	// StripQuotes removes one pair of matching double or single quotes surrounding every value before it is parsed,
+	// e.g. to read `"true"` as true when an orchestrator writes the quotes into the value. Quotes inside a value,
+	// or that do not match, are kept. It applies to getters and ParseTo, after trimming with WithTrim.
+	StripQuotes bool
+	// SecretsDir is the directory read by ParseTo for fields tagged with the `secret` option that are not configured
+	// otherwise, DefaultSecretsDir when empty.
+	SecretsDir string
+	// SensitivePatterns lists substrings of config names, matched case-insensitively, whose values WriteTo masks as
+	// if the fields were tagged with the `secure` option, e.g. to catch secrets that were not tagged. The `secure`
+	// option applies regardless. DefaultSensitivePatterns is a reasonable start; no name is matched when empty.
+	SensitivePatterns []string
+	// Trace, when set, receives one line for every key looked up, telling whether it was found and in which source,
+	// and, from ParseTo, one line for every field with the value it was assigned, or noting that it was not
+	// configured, to diagnose configurations that do not load as expected. Values of fields tagged with the `secure`
+	// option are masked. Looked up values are never written.
+	Trace io.Writer
+	// Decrypt, when set, is called by ParseTo with the full config name and the configured value of every field
+	// tagged with the `encrypted` option, e.g. to store secrets encrypted at rest in the environment. The returned
+	// value is parsed instead. Defaults are not decrypted.
+	Decrypt func(key, raw string) (string, error)
+	// OnField, when set, is called by ParseTo after every field is assigned, with the config name of the field,
+	// without the prefix, and its new value. The value of a field tagged with the `secure` option is replaced by
+	// the string "********".
+	OnField func(key string, value interface{})
+
+	// Per-call settings applied by GetOption.
+	trim bool
+	// emptyAsUnset treats empty values as not configured, see WithEmptyAsUnset.
+	emptyAsUnset bool
+	// dropEmpty removes empty elements from array values, see WithDropEmpty.
+	dropEmpty bool
+	// numericBool accepts integers as booleans, see WithNumericBool.
+	numericBool bool
+	// autoSeparator infers the separator of array values, see WithAutoSeparator.
+	autoSeparator bool
+	// ctx bounds the lookups in Sources, see ParseToCtx.
+	ctx context.Context
 }
 
+// getConfigName returns the full key of name, with the Prefix expanded. When the Prefix cannot be expanded, it is
+// kept as written, so that the key cannot be mistaken for one that exists; lookups report the error.
 func (sc ServiceConfig) getConfigName(name string) string {
-	return sc.Prefix + "_" + name
+	prefix, err := sc.expandPrefix()
+	if err != nil {
+		prefix = sc.Prefix
+	}
+	return prefix + sc.keySeparator() + name
+}
+
+// keySeparator returns the KeySeparator, or "_" when it is empty.
+func (sc ServiceConfig) keySeparator() string {
+	if sc.KeySeparator == "" {
+		return "_"
+	}
+
+	return sc.KeySeparator
+}
+
+// environmentName returns the config name of the Environment overlay of name, without the prefix.
+func (sc ServiceConfig) environmentName(name string) string {
+	return strings.ToUpper(sc.Environment) + sc.keySeparator() + name
+}
+
+// expandPrefix returns the Prefix with the environment variables it references expanded, and an error naming the
+// first referenced variable that is not set or empty.
+func (sc ServiceConfig) expandPrefix() (string, error) {
+	if !strings.Contains(sc.Prefix, "$") {
+		return sc.Prefix, nil
+	}
+
+	var missing string
+	prefix := os.Expand(sc.Prefix, func(name string) string {
+		value, _ := sc.lookupEnv(name)
+		if value == "" && missing == "" {
+			missing = name
+		}
+		return value
+	})
+	if missing != "" {
+		return prefix, fmt.Errorf("prefix %s references %s, which is not set or empty", sc.Prefix, missing)
+	}
+
+	return prefix, nil
+}
+
+// lookupEnv looks key up with LookupFunc, or in the process environment when it is nil.
+func (sc ServiceConfig) lookupEnv(key string) (string, bool) {
+	if sc.LookupFunc != nil {
+		return sc.LookupFunc(key)
+	}
+
+	return os.LookupEnv(key)
+}
+
+// sourceLookup looks key up in source, through LookupFunc when it is set and source is an EnvSource, or bounded by
+// the context of the call otherwise.
+func (sc ServiceConfig) sourceLookup(source Source, key string) (string, bool, error) {
+	if _, ok := source.(EnvSource); ok && sc.LookupFunc != nil {
+		value, found := sc.LookupFunc(key)
+		return value, found, nil
+	}
+
+	return sourceLookup(sc.ctx, source, key)
+}
+
+// lookup returns the raw value of the config with the given name, preferring the Environment overlay if set.
+func (sc ServiceConfig) lookup(name string) (string, bool, error) {
+	configData, _, exist, err := sc.resolve(name)
+	return configData, exist, err
+}
+
+// resolve is like lookup, but also returns the name of the source the value came from.
+func (sc ServiceConfig) resolve(name string) (string, string, bool, error) {
+	configData, origin, exist, err := sc.resolveKey(name)
+	configData, exist = sc.clean(configData, exist)
+	return configData, origin, exist, err
+}
+
+// resolveChunked is like resolve, but reads the value from the configs NAME_1, NAME_2, and so on, joined with the
+// KeySeparator and concatenated in order until the next index does not exist. The value exists when NAME_1 exists, and
+// comes from where NAME_1 does. When the Environment overlay has NAME_1, all chunks are read from the overlay, so that
+// a value never mixes overlay and base chunks.
+func (sc ServiceConfig) resolveChunked(name string) (string, string, bool, error) {
+	_, err := sc.expandPrefix()
+	if err != nil {
+		return "", "", false, err
+	}
+
+	base, err := sc.chunkBase(name)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	var b strings.Builder
+	var origin string
+	chunks := 0
+	for {
+		chunk, chunkOrigin, exist, err := sc.lookupKey(sc.chunkKey(base, chunks+1))
+		if err != nil {
+			return "", "", false, err
+		}
+		if !exist {
+			break
+		}
+
+		if chunks == 0 {
+			origin = chunkOrigin
+		}
+		b.WriteString(chunk)
+		chunks++
+	}
+
+	configData, exist := sc.clean(b.String(), chunks > 0)
+	return configData, origin, exist, nil
+}
+
+// clean applies the per-call settings to a value that was just looked up.
+func (sc ServiceConfig) clean(configData string, exist bool) (string, bool) {
+	if sc.trim {
+		configData = strings.TrimSpace(configData)
+	}
+	if sc.StripQuotes {
+		configData = stripQuotes(configData)
+	}
+	if sc.emptyAsUnset && configData == "" {
+		exist = false
+	}
+
+	return configData, exist
+}
+
+// stripQuotes removes one pair of matching double or single quotes surrounding s, if any.
+func stripQuotes(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+
+	return s
+}
+
+// chunkBase returns the name whose chunks hold the value of the `chunked` config name: the Environment overlay of
+// name when it has a first chunk, or name itself otherwise.
+func (sc ServiceConfig) chunkBase(name string) (string, error) {
+	if sc.Environment == "" {
+		return name, nil
+	}
+
+	_, _, exist, err := sc.lookupKey(sc.chunkKey(sc.environmentName(name), 1))
+	if err != nil {
+		return "", err
+	}
+	if exist {
+		return sc.environmentName(name), nil
+	}
+
+	return name, nil
+}
+
+// chunkKey returns the full key of the chunk idx of base.
+func (sc ServiceConfig) chunkKey(base string, idx int) string {
+	return sc.getConfigName(base + sc.keySeparator() + strconv.Itoa(idx))
+}
+
+func (sc ServiceConfig) resolveKey(name string) (string, string, bool, error) {
+	_, err := sc.expandPrefix()
+	if err != nil {
+		return "", "", false, err
+	}
+
+	if sc.Environment != "" {
+		configData, origin, exist, err := sc.lookupKey(sc.getConfigName(sc.environmentName(name)))
+		if err != nil || exist {
+			return configData, origin, exist, err
+		}
+	}
+
+	return sc.lookupKey(sc.getConfigName(name))
+}
+
+// lookupKey returns the value of the fully composed key from the first of Sources that has it, or from the
+// environment when there are no Sources, along with the name of the source it came from.
+func (sc ServiceConfig) lookupKey(key string) (string, string, bool, error) {
+	if sc.Sources == nil {
+		configData, exist := sc.lookupEnv(key)
+		if exist {
+			sc.tracef("lookup %s: found in %s", key, ProvenanceEnv)
+		} else {
+			sc.tracef("lookup %s: not found", key)
+		}
+		return configData, ProvenanceEnv, exist, nil
+	}
+
+	for _, source := range sc.Sources {
+		if sc.ctx != nil && sc.ctx.Err() != nil {
+			return "", "", false, fmt.Errorf("cannot look up %s: %w", key, sc.ctx.Err())
+		}
+
+		configData, exist, err := sc.sourceLookup(source, key)
+		if err != nil {
+			sc.tracef("lookup %s: error from %s: %v", key, sourceName(source), err)
+			return "", "", false, fmt.Errorf("cannot look up %s: %w", key, err)
+		}
+		if exist {
+			sc.tracef("lookup %s: found in %s", key, sourceName(source))
+			return configData, sourceName(source), true, nil
+		}
+	}
+
+	sc.tracef("lookup %s: not found", key)
+	return "", "", false, nil
+}
+
+func (sc ServiceConfig) GetString(name string, opts ...GetOption) (string, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		return "", ErrConfigNotFound
+	}
+	return configData, nil
+}
+
+func (sc ServiceConfig) GetStringArray(name string, opts ...GetOption) ([]string, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+
+	return sc.split(configData)
+}
+
+// RangeStringArray calls fn for every element of the config value split using ArraySeparator, in order, stopping
+// early when fn returns false. The elements are the same as those returned by GetStringArray, but they are found
+// one at a time by scanning the value, so large lists are never materialized as a slice.
+func (sc ServiceConfig) RangeStringArray(name string, fn func(string) bool, opts ...GetOption) error {
+	sc = sc.withOptions(opts)
+	yield := func(s string) bool {
+		if sc.trim {
+			s = strings.TrimSpace(s)
+		}
+		if sc.dropEmpty && s == "" {
+			return true
+		}
+		return fn(s)
+	}
+
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return ErrConfigNotFound
+	}
+
+	err = sc.checkArraySeparator()
+	if err != nil {
+		return err
+	}
+
+	sep, ok := sc.arraySeparator(configData)
+	if !ok {
+		yield(configData)
+		return nil
+	}
+
+	if sep == "" {
+		for _, r := range configData {
+			if !yield(string(r)) {
+				return nil
+			}
+		}
+		return nil
+	}
+
+	for {
+		i := strings.Index(configData, sep)
+		if i < 0 {
+			yield(configData)
+			return nil
+		}
+
+		if !yield(configData[:i]) {
+			return nil
+		}
+		configData = configData[i+len(sep):]
+	}
+}
+
+// GetStringArrayMapped splits the config value the same way as GetStringArray, and converts every element with fn.
+// It is a function rather than a method because methods cannot have type parameters. An error returned by fn is
+// wrapped with the element index and the config name.
+func GetStringArrayMapped[T any](sc ServiceConfig, name string, fn func(string) (T, error), opts ...GetOption) ([]T, error) {
+	configDataArray, err := sc.GetStringArray(name, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	mapped := make([]T, 0, len(configDataArray))
+	for i, v := range configDataArray {
+		m, err := fn(v)
+		if err != nil {
+			return nil, fmt.Errorf("config name %s element %d cannot be mapped: %w", sc.getConfigName(name), i, err)
+		}
+		mapped = append(mapped, m)
+	}
+
+	return mapped, nil
+}
+
+// GetStringArrayFiltered splits the config value the same way as GetStringArray, and returns only the elements for
+// which keep returns true, in order, e.g. to skip entries commented out with "#". When no element is kept, an empty
+// slice is returned without error.
+func (sc ServiceConfig) GetStringArrayFiltered(name string, keep func(string) bool, opts ...GetOption) ([]string, error) {
+	configDataArray, err := sc.GetStringArray(name, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]string, 0, len(configDataArray))
+	for _, v := range configDataArray {
+		if keep(v) {
+			filtered = append(filtered, v)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (sc ServiceConfig) GetIntArray(name string, opts ...GetOption) ([]int, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+
+	return sc.parseIntArray(name, configData)
+}
+
+func (sc ServiceConfig) parseIntArray(name string, configData string) ([]int, error) {
+	err := sc.checkNumberGrouping()
+	if err != nil {
+		return nil, err
+	}
+
+	configDataArray, err := sc.split(configData)
+	if err != nil {
+		return nil, err
+	}
+
+	casted := make([]int, 0, len(configDataArray))
+	for _, v := range configDataArray {
+		n, err := sc.parseInt(v)
+		if err != nil {
+			return nil, fmt.Errorf("config name %s cannot be parsed: %w", name, err)
+		}
+		casted = append(casted, n)
+	}
+
+	return casted, nil
+}
+
+// GetIntArrayBase returns the config value as an array of integers parsed in the given base, as accepted by
+// strconv.ParseInt. For bases 2, 8 and 16 elements may carry the matching "0b", "0o" or "0x" prefix, so a list of
+// masks like "0xFF 0x0F" parses with base 16.
+func (sc ServiceConfig) GetIntArrayBase(name string, base int, opts ...GetOption) ([]int, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+
+	return sc.parseIntArrayBase(name, configData, base)
+}
+
+func (sc ServiceConfig) parseIntArrayBase(name string, configData string, base int) ([]int, error) {
+	err := sc.checkNumberGrouping()
+	if err != nil {
+		return nil, err
+	}
+
+	configDataArray, err := sc.split(configData)
+	if err != nil {
+		return nil, err
+	}
+
+	casted := make([]int, 0, len(configDataArray))
+	for i, v := range configDataArray {
+		n, err := strconv.ParseInt(trimBasePrefix(sc.ungroup(v), base), base, 0)
+		if err != nil {
+			return nil, fmt.Errorf("config name %s element %d cannot be parsed in base %d: %w", name, i, base, err)
+		}
+		casted = append(casted, int(n))
+	}
+
+	return casted, nil
+}
+
+// trimBasePrefix removes the literal prefix matching base from s, keeping its sign.
+func trimBasePrefix(s string, base int) string {
+	var prefix string
+	switch base {
+	case 2:
+		prefix = "0b"
+	case 8:
+		prefix = "0o"
+	case 16:
+		prefix = "0x"
+	default:
+		return s
+	}
+
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+
+	if len(s) >= 2 && strings.EqualFold(s[:2], prefix) {
+		s = s[2:]
+	}
+
+	return sign + s
+}
+
+// GetStringMap returns the config value as a map. The value is split into entries using ArraySeparator, and each
+// entry is split into a key and a value on its first "=", so values may contain "=" themselves. Empty entries are
+// skipped, an entry without "=" or with an empty key is an error, and when a key is repeated the last entry wins.
+//
+// For example, with ArraySeparator " ", the value "a=1 b=2 a=3" is parsed into map[a:3 b:2].
+func (sc ServiceConfig) GetStringMap(name string, opts ...GetOption) (map[string]string, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+
+	return sc.parseStringMap(name, configData)
+}
+
+func (sc ServiceConfig) parseStringMap(name string, configData string) (map[string]string, error) {
+	entries, err := sc.split(configData)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]string)
+	for _, entry := range entries {
+		if entry == "" {
+			continue
+		}
+
+		key, value, err := splitPair(name, entry)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = value
+	}
+
+	return m, nil
+}
+
+// parseTextKeyMap parses configData into field, a map whose key type implements encoding.TextUnmarshaler through a
+// pointer, the same way as parseStringMap. Every key is decoded with UnmarshalText and every value with setField.
+func (sc ServiceConfig) parseTextKeyMap(field reflect.Value, name string, configData string) error {
+	entries, err := sc.split(configData)
+	if err != nil {
+		return err
+	}
+
+	m := reflect.MakeMap(field.Type())
+	for _, entry := range entries {
+		if entry == "" {
+			continue
+		}
+
+		key, value, err := splitPair(name, entry)
+		if err != nil {
+			return err
+		}
+
+		k := reflect.New(field.Type().Key())
+		err = k.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key))
+		if err != nil {
+			return fmt.Errorf("config name %s entry `%s` has a key that cannot be decoded: %w", name, entry, err)
+		}
+
+		v := reflect.New(field.Type().Elem()).Elem()
+		err = sc.setField(v, name, value, nil)
+		if err != nil {
+			return fmt.Errorf("config name %s entry `%s` has a value that cannot be parsed: %w", name, entry, err)
+		}
+
+		m.SetMapIndex(k.Elem(), v)
+	}
+
+	field.Set(m)
+	return nil
+}
+
+// splitPair splits a map entry into its key and value on the first "=".
+func splitPair(name string, entry string) (string, string, error) {
+	key, value, ok := strings.Cut(entry, "=")
+	if !ok {
+		return "", "", fmt.Errorf("config name %s has entry `%s` without a key-value separator \"=\"", name, entry)
+	}
+	if key == "" {
+		return "", "", fmt.Errorf("config name %s has entry `%s` without a key", name, entry)
+	}
+
+	return key, value, nil
+}
+
+func (sc ServiceConfig) GetInt(name string, opts ...GetOption) (int, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return 0, ErrConfigNotFound
+	}
+	return sc.parseInt(configData)
 }
 
-func (sc ServiceConfig) GetString(name string) (string, error) {
-	configData, exist := os.LookupEnv(sc.getConfigName(name))
-	if !exist {
-		return "", ErrConfigNotFound
+// GetBool parses the config value with strconv.ParseBool, which tolerates 1, t, T, TRUE, true and True, and their
+// false counterparts. See GetBoolStrict to only accept true or false.
+//
+// With the WithNumericBool option, any decimal integer is also accepted: 0 is false, and every other integer, such
+// as -1 or 2, is true.
+func (sc ServiceConfig) GetBool(name string, opts ...GetOption) (bool, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return false, err
+	}
+	if !exist {
+		return false, ErrConfigNotFound
+	}
+	return sc.parseBool(configData)
+}
+
+// GetBoolStrict is like GetBool, but only accepts exactly "true" or "false", rejecting any other form to avoid
+// ambiguity. ParseTo applies it to bool fields tagged with the `strictbool` option.
+func (sc ServiceConfig) GetBoolStrict(name string, opts ...GetOption) (bool, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return false, err
+	}
+	if !exist {
+		return false, ErrConfigNotFound
+	}
+
+	val, err := parseBoolStrict(configData)
+	if err != nil {
+		return false, sc.reformatParseError(name, err)
+	}
+	return val, nil
+}
+
+// parseBool parses s with strconv.ParseBool, also accepting integers when numericBool is set.
+func (sc ServiceConfig) parseBool(s string) (bool, error) {
+	if sc.numericBool {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err == nil {
+			return n != 0, nil
+		}
+	}
+
+	return strconv.ParseBool(s)
+}
+
+func parseBoolStrict(s string) (bool, error) {
+	switch s {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid boolean %q, expected true or false", s)
+	}
+}
+
+func (sc ServiceConfig) GetFloat32(name string, opts ...GetOption) (float32, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return 0, ErrConfigNotFound
+	}
+	number, err := sc.parseFloat(configData, 32)
+	return float32(number), err
+}
+
+func (sc ServiceConfig) GetFloat64(name string, opts ...GetOption) (float64, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return 0, ErrConfigNotFound
+	}
+	number, err := sc.parseFloat(configData, 64)
+	return number, err
+}
+
+// GetBytes returns the config value decoded from standard base64.
+func (sc ServiceConfig) GetBytes(name string, opts ...GetOption) ([]byte, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+	return base64.StdEncoding.DecodeString(configData)
+}
+
+// GetHexBytes returns the config value decoded from hexadecimal.
+func (sc ServiceConfig) GetHexBytes(name string, opts ...GetOption) ([]byte, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+	return hex.DecodeString(configData)
+}
+
+// GetJSON unmarshals the config value as JSON into out, which must be a non-nil pointer.
+func (sc ServiceConfig) GetJSON(name string, out interface{}, opts ...GetOption) error {
+	sc = sc.withOptions(opts)
+	assertPointer(out)
+
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return ErrConfigNotFound
+	}
+
+	err = decodeJSON(configData, out)
+	if err != nil {
+		return sc.reformatParseError(name, err)
+	}
+
+	return nil
+}
+
+// GetJSONArray unmarshals the config value, a JSON array, into out, which must be a non-nil pointer to a slice.
+// It is convenient for lists of structured entries, e.g. `[{"name": "a", "port": 80}]` into a *[]Endpoint.
+// ParseTo decodes slice fields tagged with the `json` option the same way.
+func (sc ServiceConfig) GetJSONArray(name string, out interface{}, opts ...GetOption) error {
+	sc = sc.withOptions(opts)
+	assertPointer(out)
+	if reflect.TypeOf(out).Elem().Kind() != reflect.Slice {
+		panic("given value is not a pointer to a slice")
+	}
+
+	return sc.GetJSON(name, out)
+}
+
+func (sc ServiceConfig) GetStringWithDefault(name string, defaultValue string, opts ...GetOption) (string, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		return defaultValue, nil
+	}
+	return configData, nil
+}
+
+// GetStringArrayWithDefault returns the config value split using ArraySeparator, or defaultValue when the config is
+// not configured. A config may be in one of three states:
+//
+//   - unset: defaultValue is returned.
+//   - empty: an empty slice is returned, rather than a single empty element. With the WithEmptyAsUnset option, an
+//     empty config is treated as unset and defaultValue is returned instead.
+//   - populated: the elements of the value are returned.
+//
+// The other array getters with a default handle these states the same way.
+func (sc ServiceConfig) GetStringArrayWithDefault(name string, defaultValue []string, opts ...GetOption) ([]string, error) {
+	sc = sc.withOptions(opts)
+	return arrayWithDefault(sc, name, defaultValue, sc.split)
+}
+
+// GetIntArrayWithDefault returns the config value parsed like GetIntArray, defaultValue when the config does not
+// exist, or an empty slice when it is empty, see arrayWithDefault.
+func (sc ServiceConfig) GetIntArrayWithDefault(name string, defaultValue []int, opts ...GetOption) ([]int, error) {
+	sc = sc.withOptions(opts)
+	return arrayWithDefault(sc, name, defaultValue, func(configData string) ([]int, error) {
+		return sc.parseIntArray(name, configData)
+	})
+}
+
+// arrayWithDefault implements the array getters with a default, so that they all treat missing and empty values the
+// same way: defaultValue is returned when the config does not exist, and an empty slice when it is empty, rather than
+// a slice holding one empty element. Otherwise, the value is parsed with parse. To fall back to defaultValue for
+// empty values too, see WithEmptyAsUnset.
+func arrayWithDefault[T any](sc ServiceConfig, name string, defaultValue []T, parse func(configData string) ([]T, error)) ([]T, error) {
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return defaultValue, nil
+	}
+	if configData == "" {
+		return []T{}, nil
+	}
+
+	return parse(configData)
+}
+
+// GetStringMapWithDefault returns the config value parsed as GetStringMap does, or defaultValue when the config
+// does not exist.
+func (sc ServiceConfig) GetStringMapWithDefault(name string, defaultValue map[string]string, opts ...GetOption) (map[string]string, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return defaultValue, nil
+	}
+
+	return sc.parseStringMap(name, configData)
+}
+
+func (sc ServiceConfig) GetIntWithDefault(name string, defaultValue int, opts ...GetOption) (int, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return defaultValue, nil
+	}
+	return sc.parseInt(configData)
+}
+
+func (sc ServiceConfig) GetBoolWithDefault(name string, defaultValue bool, opts ...GetOption) (bool, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return false, err
+	}
+	if !exist {
+		return defaultValue, nil
+	}
+	return sc.parseBool(configData)
+}
+
+func (sc ServiceConfig) GetFloat32WithDefault(name string, defaultValue float32, opts ...GetOption) (float32, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return defaultValue, nil
+	}
+	number, err := sc.parseFloat(configData, 32)
+	return float32(number), err
+}
+
+func (sc ServiceConfig) GetFloat64WithDefault(name string, defaultValue float64, opts ...GetOption) (float64, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return defaultValue, nil
+	}
+	number, err := sc.parseFloat(configData, 64)
+	return number, err
+}
+
+// ParseTo accepts a pointer to a struct with fields already tagged with `config` tags.
+// The `config` tag value indicates the name of the configuration to retrieve from. For example, a struct
+// field of type int with `config:"PORT"` tag and ServiceConfig.Prefix set with "WEB", will have the value retrieved
+// from an environment variable "WEB_PORT", and automatically parsed as integer.
+//
+// When the environment variable does not exist, the field is skipped. This way you can supply a prefilled struct that
+// already have default values initialized. If the environment variable for the field does not exist (not configured
+// by administrator of the service), then default value is used.
+//
+// Options may follow the name in the tag, separated by commas. Byte slices and fixed-size byte arrays are decoded
+// from base64 by default, or from hex when tagged with the `hex` option, e.g. `config:"KEY,hex"`. A fixed-size
+// array must receive exactly as many bytes as its length. Integers and integer slices accept a `base` option, e.g.
+// `config:"MASKS,base=16"`, see GetIntArrayBase.
+//
+// A field may be computed from other configs instead of being read, using the `compute` option with `-` as the name,
+// e.g. `config:"-,compute=https://{HOST}:{PORT}"`. Computed fields are filled after all other fields, and each
+// {NAME} reference is replaced with the parsed value of the field tagged with NAME, or with the environment variable
+// of NAME when no field has it. A reference that cannot be resolved is an error.
+//
+// Durations and duration slices are parsed with time.ParseDuration, every element on its own so that units may be
+// mixed. Bare numbers are rejected unless a unit is given with the `unit` option, e.g. `config:"TIMEOUTS,unit=s"`.
+// A duration field tagged with the `clock` option is parsed as HH:MM:SS or MM:SS instead, see GetClockDuration.
+//
+// Bool fields are parsed with strconv.ParseBool, or only from "true" and "false" when tagged with the `strictbool`
+// option, see GetBoolStrict. The `numeric_bool` option also accepts any integer, nonzero being true, see GetBool.
+//
+// Fields of type time.Time and []time.Time are parsed as RFC 3339, or with the layout registered under the name given
+// by the `layout` option, e.g. `config:"DATE,layout=dateonly"`. See RegisterTimeLayout.
+//
+// Fields of other types that implement encoding.TextUnmarshaler, directly or through a pointer, are decoded with
+// UnmarshalText. This includes, for example, slog.Level, which accepts "debug", "info", "warn" and "error".
+// Otherwise, fields implementing sql.Scanner, directly or through a pointer, are decoded by calling Scan with the
+// config value as a string, e.g. sql.NullString. Failing both, fields implementing json.Unmarshaler, directly or
+// through a pointer, are decoded with UnmarshalJSON. A value that is not valid JSON is quoted as a JSON string
+// first, so that both `{"a":1}` and `hello` can be configured, while a value such as `42` or `true` is passed as is.
+//
+// The value a field is parsed from is also stored, as is, into a string field named after it with the suffix "Raw"
+// when the field has the `keepraw` option, e.g. `config:"TIMEOUT,keepraw"` on Timeout fills TimeoutRaw with "1m30s",
+// which is useful to report configs in their original form. The companion field is not tagged itself; it is an error
+// when it does not exist or is not a string.
+//
+// Fields tagged with the `secret` option that are not configured are read from a file named after their full config
+// name in lower case in SecretsDir, following the Docker Swarm convention of mounting secrets in /run/secrets, e.g.
+// `config:"DB_PASSWORD,secret"` with Prefix "MYAPP" is read from /run/secrets/myapp_db_password. A trailing newline
+// is removed. When the file does not exist either, the default or required handling applies as usual.
+//
+// Fields of type *big.Float are parsed with a precision of 64 bits, or the number of bits given by the `prec` option,
+// e.g. `config:"RATE,prec=200"`. See GetBigFloat.
+//
+// Fields of type map[string]string are parsed from key-value pairs, see GetStringMap. The `pairs` option states this
+// format explicitly, e.g. `config:"HEADERS,pairs"` for "Content-Type=application/json Accept=*/*". Maps whose key
+// type implements encoding.TextUnmarshaler through a pointer are parsed from key-value pairs too, decoding every key
+// with UnmarshalText and every value like a field of the value type, e.g. a map[slog.Level]int from "debug=0 info=1".
+//
+// Duplicate elements of slice fields tagged with the `unique` option are removed, see GetStringArrayUnique. Slice
+// fields tagged with the `distinct` option are rejected instead when they hold duplicates, e.g. to catch an allowlist
+// entry pasted twice.
+//
+// Array fields tagged with the `autosep` option are split using the first of AutoSeparators found in the value, see
+// WithAutoSeparator.
+//
+// Slice fields of strings or numbers tagged with the `sorted` option are sorted, see GetStringArraySorted.
+//
+// Slice fields tagged with the `pad` option, N:default, are padded with the default element up to N elements, e.g.
+// `config:"WEIGHTS,pad=3:1"` reads "5" as [5 1 1], for positional configs whose trailing values are optional. An empty
+// value is padded from no elements. Longer lists are left as they are; add `maxlen=N` to reject them. Padding happens
+// before the `len`, `minlen` and `maxlen` constraints are checked, so `minlen` up to N always holds.
+//
+// String slices tagged with the `csv` option are parsed as a single CSV record, see GetStringCSV.
+//
+// The `sep` and `trim` options change how a single field is read, the same way as the WithSeparator and WithTrim
+// options change a single getter call, e.g. `config:"HOSTS,sep=;,trim"`.
+//
+// The `default` option gives the value to parse when a config does not exist, e.g. `config:"PORT,default=8080"`,
+// overriding the prefilled value. Defaults may reference other configs with {NAME}, e.g.
+// `config:"URL,default=https://{HOST}:{PORT}"`, which resolves to the configured value of NAME, or to its default
+// when it is not configured. Defaults referencing each other in a cycle are an error. Since options are separated by
+// commas, a default cannot contain a comma.
+//
+// A default may depend on the Environment, with an option named after it in lower case, e.g.
+// `config:"PORT,default_production=443,default_development=8080,default=80"`. When a config does not exist, the
+// default for the Environment is used, then the `default_func` option, and finally the `default` option.
+//
+// The `default_func` option names a function registered with RegisterDefaultFunc that produces the value when a
+// config does not exist, e.g. `config:"NODE,default_func=hostname"`. It takes precedence over the `default` option.
+// The `provider` option names a function registered with RegisterProvider, called as a last resort when a config
+// does not exist and has no default of any kind, e.g. `config:"INSTANCE_ID,provider=generateID"`.
+//
+// A field tagged with the `required` option, or any field when RequireAll is set, must be configured or have a
+// default. ParseTo then returns a MissingConfigError listing all missing required configs.
+//
+// Parsed values are checked against the constraints given by the `oneof`, `pattern`, `min` and `max` options:
+// `oneof` lists the allowed values separated by "|", e.g. `config:"MODE,oneof=dev|prod"`, `pattern` is a regular
+// expression the whole value must match, and `min` and `max` bound numeric fields, with durations for time.Duration
+// fields, e.g. `config:"TIMEOUT,min=1s,max=1m"`. The number of elements of slice fields is bounded by `len`, `minlen`
+// and `maxlen`, e.g. `config:"REPLICAS,minlen=1,maxlen=5"`. Version fields are bounded by `minversion`, e.g.
+// `config:"API_VERSION,minversion=1.2.0"`, see GetSemver. See Describe to list the constraints of a struct.
+//
+// A value too large for a single environment variable may be split into chunks, read from a field tagged with the
+// `chunked` option, e.g. `config:"CERT,chunked"`, which concatenates CERT_1, CERT_2, and so on, until the next index
+// does not exist. The reassembled value is parsed as usual.
+//
+// Fields sharing a `group` option, e.g. `config:"DB_HOST,group=db"` and `config:"DB_USER,group=db"`, must be either
+// all configured or all not configured. A partially configured group is an error listing the offending keys.
+//
+// Fields tagged with the `encrypted` option have their configured value decrypted with the Decrypt hook before
+// being parsed.
+//
+// The Prefix may also be declared on the struct by embedding the Config marker, see Config.
+//
+// A field of any type tagged with the `json` option, e.g. `config:"ENDPOINTS,json"`, is decoded from JSON. See GetJSON.
+// Fields of type []map[string]string and []map[string]interface{} are always decoded from a JSON array of objects,
+// e.g. `[{"name": "primary", "url": "https://a"}]`.
+func (sc ServiceConfig) ParseTo(obj interface{}) error {
+	assertPointer(obj)
+	return sc.parse(obj, &parseState{})
+}
+
+// ParseToWithDefaults is like ParseTo, but every field that is not configured, and has no default given by its tag,
+// is assigned the value of the same field of the struct pointed by defaults, as if obj had been prefilled with it.
+// This keeps the defaults apart from the target, e.g. to share one defaults instance between several parses. Both obj
+// and defaults must point to structs of the same type. Slices and maps are assigned, not deeply copied, and defaults
+// is never modified.
+func (sc ServiceConfig) ParseToWithDefaults(obj interface{}, defaults interface{}) error {
+	assertPointer(obj)
+	assertPointer(defaults)
+	if reflect.TypeOf(obj) != reflect.TypeOf(defaults) {
+		panic(fmt.Sprintf("defaults of type %T cannot be used for %T", defaults, obj))
+	}
+
+	return sc.parse(obj, &parseState{defaults: configFields(defaults)})
+}
+
+// parseState carries the optional inputs and outputs of a single parse.
+type parseState struct {
+	// When not nil, the origin of every field is recorded into it.
+	provenance Provenance
+	// When not nil, the fields of the defaults struct, in the same order as the fields being parsed.
+	defaults []configField
+	// When not empty, only the fields tagged with this `group` option are parsed.
+	group string
+}
+
+// includes reports whether f is parsed.
+func (s *parseState) includes(f configField) bool {
+	if s.group == "" {
+		return true
+	}
+
+	group, _ := f.opts.get("group")
+	return group == s.group
+}
+
+// fillDefault assigns the field at index i of the defaults struct, if any, to f.
+func (s *parseState) fillDefault(i int, f configField) {
+	if s.defaults != nil {
+		f.value.Set(s.defaults[i].value)
+	}
+}
+
+// record stores the origin of the field f if provenance is being recorded.
+func (s *parseState) record(f configField, origin string) {
+	if s.provenance != nil {
+		s.provenance[f.field.Name] = origin
+	}
+}
+
+func (sc ServiceConfig) parse(obj interface{}, state *parseState) error {
+	sc = sc.withStructSettings(obj)
+	_, err := sc.expandPrefix()
+	if err != nil {
+		return err
+	}
+	if sc.NoExtraKeys && state.group == "" && (sc.Sources != nil || sc.LookupFunc != nil) {
+		return errors.New("NoExtraKeys can only be used when configs are read from the process environment, " +
+			"without Sources or LookupFunc")
+	}
+
+	fields := configFields(obj)
+	computed := make([]configField, 0)
+	missing := make([]string, 0)
+	groups := make(fieldGroups)
+	for i, f := range fields {
+		if f.name == "" {
+			return sc.reformatParseError(f.tag, fmt.Errorf("unable to parse config for tag `%s`: invalid tag parts", f.tag))
+		}
+
+		if !state.includes(f) {
+			continue
+		}
+
+		if f.opts.has("compute") {
+			computed = append(computed, f)
+			continue
+		}
+
+		fsc := sc.withOptions(f.opts.getOptions())
+		configData, origin, exist, err := fsc.resolveField(f)
+		if err != nil {
+			return sc.reformatParseError(f.name, err)
+		}
+		if !exist && f.opts.has("secret") {
+			configData, origin, exist, err = fsc.resolveSecret(f.name)
+			if err != nil {
+				return sc.reformatParseError(f.name, err)
+			}
+		}
+		if group, ok := f.opts.get("group"); ok {
+			groups.add(group, sc.getConfigName(f.name), exist)
+		}
+		if exist && f.opts.has("encrypted") {
+			configData, err = sc.decrypt(f.name, configData)
+			if err != nil {
+				return sc.reformatParseError(f.name, err)
+			}
+		}
+		if fn, ok := f.opts.get("default_func"); !exist && ok && !sc.hasEnvironmentDefault(f) {
+			configData, err = callDefaultFunc(fn)
+			if err != nil {
+				return sc.reformatParseError(f.name, err)
+			}
+			origin, exist = ProvenanceDefault, true
+		}
+		if _, ok := sc.defaultOption(f); !exist && ok {
+			configData, err = fsc.expandDefault(fields, f, nil)
+			if err != nil {
+				return sc.reformatParseError(f.name, err)
+			}
+			origin, exist = ProvenanceDefault, true
+		}
+		if fn, ok := f.opts.get("provider"); !exist && ok {
+			configData, err = callProvider(fn)
+			if err != nil {
+				return sc.reformatParseError(f.name, err)
+			}
+			origin, exist = ProvenanceDefault, true
+		}
+		if !exist && sc.isRequired(f) {
+			missing = append(missing, sc.getConfigName(f.name))
+			continue
+		}
+		if !exist {
+			if sc.ZeroMissing {
+				f.value.Set(reflect.Zero(f.value.Type()))
+			}
+			state.fillDefault(i, f)
+			if f.value.IsZero() {
+				state.record(f, ProvenanceUnset)
+			} else {
+				state.record(f, ProvenanceDefault)
+			}
+			sc.tracef("keep %s: not configured", f.field.Name)
+			continue
+		}
+
+		err = fsc.setField(f.value, f.name, configData, f.opts)
+		if err != nil {
+			return sc.reformatParseError(f.name, err)
+		}
+		err = f.setRaw(configData)
+		if err != nil {
+			return sc.reformatParseError(f.name, err)
+		}
+		err = validateField(f)
+		if err != nil {
+			return sc.reformatParseError(f.name, err)
+		}
+		state.record(f, origin)
+		sc.traceField(f, origin)
+		sc.notifyField(f)
 	}
-	return configData, nil
-}
 
-func (sc ServiceConfig) GetStringArray(name string) ([]string, error) {
-	configData, exist := os.LookupEnv(sc.getConfigName(name))
-	configDataArray := strings.Split(configData, sc.ArraySeparator)
-	if !exist {
-		return nil, ErrConfigNotFound
+	err = groups.check()
+	if err != nil {
+		return err
 	}
 
-	return configDataArray, nil
-}
+	if len(missing) > 0 {
+		return &MissingConfigError{Keys: missing}
+	}
 
-func (sc ServiceConfig) GetIntArray(name string) ([]int, error) {
-	configData, exist := os.LookupEnv(sc.getConfigName(name))
-	configDataArray := strings.Split(configData, sc.ArraySeparator)
-	if !exist {
-		return nil, ErrConfigNotFound
+	if sc.NoExtraKeys && state.group == "" {
+		if extra := sc.UnusedKeys(obj); len(extra) > 0 {
+			return &ExtraKeysError{Keys: extra}
+		}
 	}
 
-	casted := make([]int, 0, len(configDataArray))
-	for _, v := range configDataArray {
-		n, err := strconv.Atoi(v)
+	for _, f := range computed {
+		template, _ := f.opts.get("compute")
+		configData, err := interpolate(template, func(ref string) (string, error) {
+			return sc.resolveReference(fields, ref)
+		})
 		if err != nil {
-			return nil, fmt.Errorf("config name %s cannot be parsed: %w", name, err)
+			return sc.reformatParseError(f.key(), err)
 		}
-		casted = append(casted, n)
+
+		err = sc.withOptions(f.opts.getOptions()).setField(f.value, f.key(), configData, f.opts)
+		if err != nil {
+			return sc.reformatParseError(f.key(), err)
+		}
+		err = f.setRaw(configData)
+		if err != nil {
+			return sc.reformatParseError(f.key(), err)
+		}
+		err = validateField(f)
+		if err != nil {
+			return sc.reformatParseError(f.key(), err)
+		}
+		state.record(f, ProvenanceComputed)
+		sc.traceField(f, ProvenanceComputed)
+		sc.notifyField(f)
 	}
 
-	return casted, nil
+	return nil
 }
 
-func (sc ServiceConfig) GetInt(name string) (int, error) {
-	configData, exist := os.LookupEnv(sc.getConfigName(name))
-	if !exist {
-		return 0, ErrConfigNotFound
+// notifyField calls the OnField hook, if any, with the value of f.
+func (sc ServiceConfig) notifyField(f configField) {
+	if sc.OnField == nil {
+		return
 	}
-	return strconv.Atoi(configData)
-}
 
-func (sc ServiceConfig) GetBool(name string) (bool, error) {
-	configData, exist := os.LookupEnv(sc.getConfigName(name))
-	if !exist {
-		return false, ErrConfigNotFound
+	var value interface{} = "********"
+	if !f.opts.has("secure") {
+		value = f.value.Interface()
 	}
-	return strconv.ParseBool(configData)
+	sc.OnField(f.key(), value)
 }
 
-func (sc ServiceConfig) GetFloat32(name string) (float32, error) {
-	configData, exist := os.LookupEnv(sc.getConfigName(name))
-	if !exist {
-		return 0, ErrConfigNotFound
+// decrypt returns the configured value of name decrypted with the Decrypt hook.
+func (sc ServiceConfig) decrypt(name, configData string) (string, error) {
+	if sc.Decrypt == nil {
+		return "", errors.New("field is tagged with encrypted, but no Decrypt hook is set")
 	}
-	number, err := strconv.ParseFloat(configData, 32)
-	return float32(number), err
-}
 
-func (sc ServiceConfig) GetFloat64(name string) (float64, error) {
-	configData, exist := os.LookupEnv(sc.getConfigName(name))
-	if !exist {
-		return 0, ErrConfigNotFound
+	value, err := sc.Decrypt(sc.getConfigName(name), configData)
+	if err != nil {
+		return "", fmt.Errorf("cannot decrypt: %w", err)
 	}
-	number, err := strconv.ParseFloat(configData, 64)
-	return number, err
+	return value, nil
 }
 
-func (sc ServiceConfig) GetStringWithDefault(name string, defaultValue string) (string, error) {
-	configData, exist := os.LookupEnv(sc.getConfigName(name))
+// resolveReference returns the value referenced by name in a compute template. The already parsed value of the field
+// with that config name is preferred, and the environment is used for names that no field has.
+func (sc ServiceConfig) resolveReference(fields []configField, name string) (string, error) {
+	for _, f := range fields {
+		if f.name == name && !f.opts.has("compute") {
+			return fmt.Sprintf("%v", f.value.Interface()), nil
+		}
+	}
+
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return "", err
+	}
 	if !exist {
-		return defaultValue, nil
+		return "", fmt.Errorf("referenced config %s: %w", name, ErrConfigNotFound)
 	}
+
 	return configData, nil
 }
 
-func (sc ServiceConfig) GetStringArrayWithDefault(name string, defaultValue []string) ([]string, error) {
-	configData, exist := os.LookupEnv(sc.getConfigName(name))
-	configDataArray := strings.Split(configData, sc.ArraySeparator)
-	if !exist {
-		return defaultValue, nil
+// expandDefault returns the `default` option of f with every {NAME} reference replaced. A reference resolves to the
+// configured value of NAME, or when it is not configured, to the expanded default of the field tagged with NAME, or
+// the current value of that field if it has no default. visiting holds the names being expanded, to detect cycles.
+func (sc ServiceConfig) expandDefault(fields []configField, f configField, visiting []string) (string, error) {
+	for i, name := range visiting {
+		if name == f.name {
+			return "", fmt.Errorf("cycle in defaults: %s", strings.Join(append(visiting[i:], f.name), " -> "))
+		}
 	}
+	visiting = append(visiting, f.name)
+
+	template, _ := sc.defaultOption(f)
+	return interpolate(template, func(ref string) (string, error) {
+		configData, exist, err := sc.lookup(ref)
+		if err != nil || exist {
+			return configData, err
+		}
+
+		for _, other := range fields {
+			if other.name != ref {
+				continue
+			}
+			if _, ok := sc.defaultOption(other); ok {
+				return sc.expandDefault(fields, other, visiting)
+			}
+			return fmt.Sprintf("%v", other.value.Interface()), nil
+		}
 
-	return configDataArray, nil
+		return "", fmt.Errorf("referenced config %s: %w", ref, ErrConfigNotFound)
+	})
 }
 
-func (sc ServiceConfig) GetIntArrayWithDefault(name string, defaultValue []int) ([]int, error) {
-	v, err := sc.GetIntArray(name)
-	if errors.Is(err, ErrConfigNotFound) {
-		return defaultValue, nil
+// defaultOption returns the default of f for the Environment, given by a `default_<environment>` option, or its
+// `default` option when it has none.
+func (sc ServiceConfig) defaultOption(f configField) (string, bool) {
+	if sc.hasEnvironmentDefault(f) {
+		return f.opts.get("default_" + strings.ToLower(sc.Environment))
 	}
 
-	return v, nil
+	return f.opts.get("default")
 }
 
-func (sc ServiceConfig) GetIntWithDefault(name string, defaultValue int) (int, error) {
-	configData, exist := os.LookupEnv(sc.getConfigName(name))
-	if !exist {
-		return defaultValue, nil
-	}
-	return strconv.Atoi(configData)
+// hasEnvironmentDefault reports whether f has a `default_<environment>` option for the Environment.
+func (sc ServiceConfig) hasEnvironmentDefault(f configField) bool {
+	return sc.Environment != "" && f.opts.has("default_"+strings.ToLower(sc.Environment))
 }
 
-func (sc ServiceConfig) GetBoolWithDefault(name string, defaultValue bool) (bool, error) {
-	configData, exist := os.LookupEnv(sc.getConfigName(name))
-	if !exist {
-		return defaultValue, nil
+// interpolate replaces every {NAME} reference in template with the value returned by resolve for NAME.
+func interpolate(template string, resolve func(name string) (string, error)) (string, error) {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			b.WriteString(template)
+			return b.String(), nil
+		}
+
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated reference in `%s`", template)
+		}
+
+		value, err := resolve(template[start+1 : start+end])
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString(template[:start])
+		b.WriteString(value)
+		template = template[start+end+1:]
 	}
-	return strconv.ParseBool(configData)
 }
 
-func (sc ServiceConfig) GetFloat32WithDefault(name string, defaultValue float32) (float32, error) {
-	configData, exist := os.LookupEnv(sc.getConfigName(name))
-	if !exist {
-		return defaultValue, nil
+// setField parses configData according to the type of field and stores the result in it.
+func (sc ServiceConfig) setField(field reflect.Value, tag string, configData string, opts tagOptions) error {
+	if pad, ok := opts.get("pad"); ok {
+		n, elem, err := sc.padElement(field, tag, pad, opts)
+		if err != nil {
+			return err
+		}
+		if configData == "" {
+			field.Set(reflect.MakeSlice(field.Type(), 0, n))
+			padField(field, n, elem)
+			return nil
+		}
+
+		defer func() {
+			if !field.IsNil() {
+				padField(field, n, elem)
+			}
+		}()
 	}
-	number, err := strconv.ParseFloat(configData, 32)
-	return float32(number), err
-}
 
-func (sc ServiceConfig) GetFloat64WithDefault(name string, defaultValue float64) (float64, error) {
-	configData, exist := os.LookupEnv(sc.getConfigName(name))
-	if !exist {
-		return defaultValue, nil
+	if opts.has("unique") {
+		if field.Kind() != reflect.Slice || !field.Type().Elem().Comparable() {
+			return fmt.Errorf("unique is only supported for slices of comparable elements, not %s", field.Type())
+		}
+		if configData == "" {
+			field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+			return nil
+		}
+
+		defer func() {
+			if !field.IsNil() {
+				uniqueField(field)
+			}
+		}()
 	}
-	number, err := strconv.ParseFloat(configData, 64)
-	return number, err
-}
 
-// ParseTo accepts a pointer to a struct with fields already tagged with `config` tags.
-// The `config` tag value indicates the name of the configuration to retrieve from. For example, a struct
-// field of type int with `config:"PORT"` tag and ServiceConfig.Prefix set with "WEB", will have the value retrieved
-// from an environment variable "WEB_PORT", and automatically parsed as integer.
-//
-// When the environment variable does not exist, the field is skipped. This way you can supply a prefilled struct that
-// already have default values initialized. If the environment variable for the field does not exist (not configured
-// by administrator of the service), then default value is used.
-func (sc ServiceConfig) ParseTo(obj interface{}) error {
-	assertPointer(obj)
+	if opts.has("sorted") {
+		if !sortable(field.Type()) {
+			return fmt.Errorf("sorted is only supported for slices of strings and numbers, not %s", field.Type())
+		}
+		if configData == "" {
+			field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+			return nil
+		}
 
-	v := reflect.ValueOf(obj)
-	realV := reflect.Indirect(v)
-	t := realV.Type()
+		defer func() {
+			if !field.IsNil() {
+				sortField(field)
+			}
+		}()
+	}
 
-	for i := 0; i < realV.NumField(); i++ {
-		tags, ok := t.Field(i).Tag.Lookup("config")
-		if !ok {
-			continue
+	if opts.has("json") {
+		return decodeJSON(configData, field.Addr().Interface())
+	}
+
+	if opts.has("pairs") && field.Type() != reflect.TypeOf(map[string]string{}) {
+		return fmt.Errorf("option `pairs` requires a map[string]string field, not %s", field.Type())
+	}
+
+	switch field.Interface().(type) {
+	case int:
+		val, err := sc.parseIntBase(configData, opts)
+		if err != nil {
+			return err
 		}
 
-		tagParts := strings.Split(tags, ",")
-		if tagParts == nil || len(tagParts) == 0 {
-			return sc.reformatParseError(tags, fmt.Errorf("unable to parse config for tag `%s`: invalid tag parts", tags))
+		field.Set(reflect.ValueOf(val))
+	case int64:
+		val, err := sc.parseIntBase(configData, opts)
+		if err != nil {
+			return err
 		}
 
-		tag := tagParts[0]
-		switch realV.Field(i).Interface().(type) {
-		case int:
-			val, err := sc.GetInt(tag)
-			if err != nil {
-				if errors.Is(err, ErrConfigNotFound) {
-					continue
-				}
+		field.Set(reflect.ValueOf(int64(val)))
+	case string:
+		field.Set(reflect.ValueOf(configData))
+	case float32:
+		val, err := sc.parseFloat(configData, 32)
+		if err != nil {
+			return err
+		}
 
-				return sc.reformatParseError(tag, err)
-			}
+		field.Set(reflect.ValueOf(float32(val)))
+	case float64:
+		val, err := sc.parseFloat(configData, 64)
+		if err != nil {
+			return err
+		}
 
-			realV.Field(i).Set(reflect.ValueOf(val))
-		case int64:
-			val, err := sc.GetInt(tag)
-			if err != nil {
-				if errors.Is(err, ErrConfigNotFound) {
-					continue
-				}
+		field.Set(reflect.ValueOf(val))
+	case bool:
+		parse := sc.parseBool
+		if opts.has("strictbool") {
+			parse = parseBoolStrict
+		}
 
-				return sc.reformatParseError(tag, err)
-			}
+		val, err := parse(configData)
+		if err != nil {
+			return err
+		}
 
-			realV.Field(i).Set(reflect.ValueOf(int64(val)))
-		case string:
-			val, err := sc.GetString(tag)
-			if err != nil {
-				if errors.Is(err, ErrConfigNotFound) {
-					continue
-				}
+		field.Set(reflect.ValueOf(val))
+	case []string:
+		split := sc.split
+		if opts.has("csv") {
+			split = parseCSV
+		}
 
-				return sc.reformatParseError(tag, err)
+		val, err := split(configData)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(val))
+	case []int:
+		var val []int
+		var err error
+		if b, ok := opts.get("base"); ok {
+			base, baseErr := strconv.Atoi(b)
+			if baseErr != nil {
+				return fmt.Errorf("invalid base option `%s`: %w", b, baseErr)
 			}
+			val, err = sc.parseIntArrayBase(tag, configData, base)
+		} else {
+			val, err = sc.parseIntArray(tag, configData)
+		}
+		if err != nil {
+			return err
+		}
 
-			realV.Field(i).Set(reflect.ValueOf(val))
-		case float32:
-			val, err := sc.GetFloat32(tag)
+		field.Set(reflect.ValueOf(val))
+	case time.Duration:
+		if opts.has("clock") {
+			val, err := parseClock(configData)
 			if err != nil {
-				if errors.Is(err, ErrConfigNotFound) {
-					continue
-				}
-
-				return sc.reformatParseError(tag, err)
+				return err
 			}
 
-			realV.Field(i).Set(reflect.ValueOf(val))
-		case float64:
-			val, err := sc.GetFloat64(tag)
-			if err != nil {
-				if errors.Is(err, ErrConfigNotFound) {
-					continue
-				}
+			field.Set(reflect.ValueOf(val))
+			return nil
+		}
 
-				return sc.reformatParseError(tag, err)
-			}
+		unit, err := parseUnit(opts)
+		if err != nil {
+			return err
+		}
+
+		val, err := parseDuration(configData, unit)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(val))
+	case []time.Duration:
+		unit, err := parseUnit(opts)
+		if err != nil {
+			return err
+		}
+
+		val, err := sc.parseDurationArray(tag, configData, unit)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(val))
+	case net.IP:
+		val, err := parseIP(configData)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(val))
+	case []net.IP:
+		val, err := sc.parseIPArray(tag, configData)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(val))
+	case time.Time:
+		name, _ := opts.get("layout")
+		layout, err := timeLayout(name)
+		if err != nil {
+			return err
+		}
+
+		val, err := time.Parse(layout, configData)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(val))
+	case []time.Time:
+		name, _ := opts.get("layout")
+		layout, err := timeLayout(name)
+		if err != nil {
+			return err
+		}
+
+		val, err := sc.parseTimeArray(tag, configData, layout)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(val))
+	case *big.Float:
+		prec, err := parsePrec(opts)
+		if err != nil {
+			return err
+		}
+
+		val, err := sc.parseBigFloat(configData, prec)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(val))
+	case *time.Location:
+		val, err := time.LoadLocation(configData)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(val))
+	case *regexp.Regexp:
+		val, err := regexp.Compile(configData)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(val))
+	case []map[string]string, []map[string]interface{}:
+		return decodeJSON(configData, field.Addr().Interface())
+	case map[string]string:
+		val, err := sc.parseStringMap(tag, configData)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(val))
+	case []byte:
+		val, err := decodeBytes(configData, opts)
+		if err != nil {
+			return err
+		}
 
-			realV.Field(i).Set(reflect.ValueOf(val))
-		case bool:
-			val, err := sc.GetBool(tag)
+		field.Set(reflect.ValueOf(val))
+	default:
+		if field.Kind() == reflect.Array && field.Type().Elem().Kind() == reflect.Uint8 {
+			val, err := decodeBytes(configData, opts)
 			if err != nil {
-				if errors.Is(err, ErrConfigNotFound) {
-					continue
-				}
+				return err
+			}
 
-				return sc.reformatParseError(tag, err)
+			if len(val) != field.Len() {
+				return fmt.Errorf("decoded %d bytes, expected exactly %d", len(val), field.Len())
 			}
 
-			realV.Field(i).Set(reflect.ValueOf(val))
-		case []string:
-			val, err := sc.GetStringArray(tag)
-			if err != nil {
-				if errors.Is(err, ErrConfigNotFound) {
-					continue
-				}
+			reflect.Copy(field, reflect.ValueOf(val))
+			return nil
+		}
 
-				return sc.reformatParseError(tag, err)
+		if field.Kind() == reflect.Map && reflect.PointerTo(field.Type().Key()).Implements(textUnmarshalerType) {
+			return sc.parseTextKeyMap(field, tag, configData)
+		}
+
+		if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(configData))
+		}
+
+		if field.Kind() == reflect.Ptr && field.Type().Implements(textUnmarshalerType) {
+			val := reflect.New(field.Type().Elem())
+			err := val.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(configData))
+			if err != nil {
+				return err
 			}
 
-			realV.Field(i).Set(reflect.ValueOf(val))
-		case []int:
-			val, err := sc.GetIntArray(tag)
+			field.Set(val)
+			return nil
+		}
+
+		if s, ok := field.Addr().Interface().(sql.Scanner); ok {
+			return s.Scan(configData)
+		}
+
+		if field.Kind() == reflect.Ptr && field.Type().Implements(scannerType) {
+			val := reflect.New(field.Type().Elem())
+			err := val.Interface().(sql.Scanner).Scan(configData)
 			if err != nil {
-				if errors.Is(err, ErrConfigNotFound) {
-					continue
-				}
+				return err
+			}
+
+			field.Set(val)
+			return nil
+		}
 
-				return sc.reformatParseError(tag, err)
+		if u, ok := field.Addr().Interface().(json.Unmarshaler); ok {
+			return u.UnmarshalJSON(jsonValue(configData))
+		}
+
+		if field.Kind() == reflect.Ptr && field.Type().Implements(jsonUnmarshalerType) {
+			val := reflect.New(field.Type().Elem())
+			err := val.Interface().(json.Unmarshaler).UnmarshalJSON(jsonValue(configData))
+			if err != nil {
+				return err
 			}
 
-			realV.Field(i).Set(reflect.ValueOf(val))
-		default:
-			panic(fmt.Sprintf("unable to parse config for tag `%s`: unknown data type: %s", tag, t.String()))
+			field.Set(val)
+			return nil
 		}
+
+		panic(fmt.Sprintf("unable to parse config for tag `%s`: unknown data type: %s", tag, field.Type().String()))
+	}
+
+	return nil
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	scannerType         = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// jsonValue returns configData as is when it is valid JSON, or quoted as a JSON string otherwise.
+func jsonValue(configData string) []byte {
+	if json.Valid([]byte(configData)) {
+		return []byte(configData)
+	}
+
+	quoted, _ := json.Marshal(configData)
+	return quoted
+}
+
+// decodeJSON unmarshals configData into out. Errors are wrapped with the Go type of out so that a value of the wrong
+// shape, such as an array given to a map, is easy to diagnose. The wrapped error still unwraps to the json error.
+func decodeJSON(configData string, out interface{}) error {
+	err := json.Unmarshal([]byte(configData), out)
+	if err != nil {
+		return fmt.Errorf("cannot decode JSON into %s: %w", reflect.TypeOf(out).Elem(), err)
 	}
 
 	return nil
 }
 
+// decodeBytes decodes configData as hex when the `hex` option is present, or as standard base64 otherwise.
+func decodeBytes(configData string, opts tagOptions) ([]byte, error) {
+	if opts.has("hex") {
+		return hex.DecodeString(configData)
+	}
+
+	return base64.StdEncoding.DecodeString(configData)
+}
+
+// reformatParseError wraps err with the full config name of name, as it was looked up.
 func (sc ServiceConfig) reformatParseError(name string, err error) error {
-	return fmt.Errorf("cannot parse %s_%s: %w", sc.Prefix, name, err)
+	return fmt.Errorf("cannot parse %s: %w", sc.getConfigName(name), err)
 }
 
 func assertPointer(value interface{}) {
@@ -296,38 +1733,97 @@ func assertPointer(value interface{}) {
 	}
 }
 
-func (sc ServiceConfig) WriteTo(obj interface{}, w io.Writer) error {
-	assertPointer(obj)
+// configField is a struct field that is tagged with a `config` tag.
+type configField struct {
+	// The value of the field, settable when the struct was given through a pointer.
+	value reflect.Value
+	// The struct field description.
+	field reflect.StructField
+	// The full `config` tag.
+	tag string
+	// The config name, which is the first part of the tag.
+	name string
+	// The options that follow the config name in the tag.
+	opts tagOptions
+	// The value of the companion field named after the field with the suffix "Raw", when the field has the `keepraw`
+	// option and the companion exists.
+	raw reflect.Value
+}
+
+// setRaw stores configData into the companion field of f, if f has the `keepraw` option.
+func (f configField) setRaw(configData string) error {
+	if !f.opts.has("keepraw") {
+		return nil
+	}
+	if !f.raw.IsValid() || f.raw.Kind() != reflect.String {
+		return fmt.Errorf("option `keepraw` requires a string field %sRaw", f.field.Name)
+	}
+
+	f.raw.SetString(configData)
+	return nil
+}
 
-	v := reflect.ValueOf(obj)
-	realV := reflect.Indirect(v)
+// key returns the name used to refer to the field in messages, which is the config name, or the struct field name
+// for fields that are not read from a config such as computed fields.
+func (f configField) key() string {
+	if f.name == "-" {
+		return f.field.Name
+	}
+
+	return f.name
+}
+
+// configFields returns all fields of the struct pointed by obj that have a `config` tag, in declaration order.
+func configFields(obj interface{}) []configField {
+	realV := reflect.Indirect(reflect.ValueOf(obj))
 	t := realV.Type()
 
-	configs := make([]string, 0)
+	fields := make([]configField, 0, realV.NumField())
 	for i := 0; i < realV.NumField(); i++ {
 		tag, ok := t.Field(i).Tag.Lookup("config")
-		if !ok {
+		if !ok || t.Field(i).Type == markerType {
 			continue
 		}
 
-		fieldValue := realV.Field(i)
-		value := fmt.Sprintf("%v", fieldValue.Interface())
+		name, opts := parseTag(tag)
+		f := configField{
+			value: realV.Field(i),
+			field: t.Field(i),
+			tag:   tag,
+			name:  name,
+			opts:  opts,
+		}
+		if opts.has("keepraw") {
+			f.raw = realV.FieldByName(t.Field(i).Name + "Raw")
+		}
+		fields = append(fields, f)
+	}
 
-		parts := strings.Split(tag, ",")
-		key := parts[0]
-		isSecure := len(parts) > 1 && parts[1] == "secure"
+	return fields
+}
 
-		if isSecure && value != "" {
-			value = "********"
-		}
+// tagOptions holds the options that follow the config name in a `config` struct tag. Options are either flags,
+// such as `secure`, or key-value pairs, such as `base=16`. Flags are stored with an empty value.
+type tagOptions map[string]string
 
-		configs = append(configs, fmt.Sprintf("%s=%s", key, value))
+// parseTag splits a `config` struct tag into the config name and its options.
+func parseTag(tag string) (string, tagOptions) {
+	parts := strings.Split(tag, ",")
+	opts := make(tagOptions, len(parts)-1)
+	for _, part := range parts[1:] {
+		key, value, _ := strings.Cut(part, "=")
+		opts[strings.TrimSpace(key)] = value
 	}
 
-	_, err := fmt.Fprintf(w, strings.Join(configs, ", "))
-	if err != nil {
-		return err
-	}
+	return strings.TrimSpace(parts[0]), opts
+}
 
-	return nil
+func (o tagOptions) has(key string) bool {
+	_, ok := o[key]
+	return ok
+}
+
+func (o tagOptions) get(key string) (string, bool) {
+	value, ok := o[key]
+	return value, ok
 }
@@ -1,11 +1,19 @@
 package config
 
 import (
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestServiceConfig_ParseTo(t *testing.T) {
@@ -100,3 +108,1194 @@ func ExampleServiceConfig_ParseTo() {
 	fmt.Print(myConfig)
 	// Output: &{80 192.168.1.1 [test test1] 1.234}
 }
+
+func TestServiceConfig_ParseTo_byteArray(t *testing.T) {
+	type TestConfig struct {
+		Key     [4]byte `config:"KEY"`
+		HexKey  [4]byte `config:"HEX_KEY,hex"`
+		RawData []byte  `config:"RAW_DATA"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "BYTES",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("BYTES_KEY", "AQIDBA==")
+	t.Setenv("BYTES_HEX_KEY", "0a0b0c0d")
+	t.Setenv("BYTES_RAW_DATA", "aGVsbG8=")
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := &TestConfig{
+		Key:     [4]byte{1, 2, 3, 4},
+		HexKey:  [4]byte{0x0a, 0x0b, 0x0c, 0x0d},
+		RawData: []byte("hello"),
+	}
+	if !reflect.DeepEqual(expect, n) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expect)
+	}
+
+	t.Setenv("BYTES_HEX_KEY", "0a0b0c")
+	err = sc.ParseTo(n)
+	if err == nil || !strings.Contains(err.Error(), "BYTES_HEX_KEY") || !strings.Contains(err.Error(), "expected exactly 4") {
+		t.Fatalf("expected length mismatch error naming the key, received: %v", err)
+	}
+}
+
+func TestServiceConfig_Environment(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "OVERLAY",
+		ArraySeparator: " ",
+		Environment:    "production",
+	}
+
+	t.Setenv("OVERLAY_PORT", "8080")
+	t.Setenv("OVERLAY_PRODUCTION_PORT", "443")
+	t.Setenv("OVERLAY_HOST", "localhost")
+
+	port, err := sc.GetInt("PORT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port != 443 {
+		t.Fatalf("expected overlay value 443, received: %d", port)
+	}
+
+	host, err := sc.GetString("HOST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host != "localhost" {
+		t.Fatalf("expected base value localhost, received: %s", host)
+	}
+}
+
+func TestServiceConfig_GetIntArrayBase(t *testing.T) {
+	type TestConfig struct {
+		Masks []int `config:"MASKS,base=16"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "BASE",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("BASE_MASKS", "0xFF 0x0F 10")
+
+	masks, err := sc.GetIntArrayBase("MASKS", 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []int{0xFF, 0x0F, 0x10}
+	if !reflect.DeepEqual(expect, masks) {
+		t.Fatalf("parsed array is not the same with expectation, received: %v, expected: %v", masks, expect)
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expect, n.Masks) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n.Masks, expect)
+	}
+
+	t.Setenv("BASE_MASKS", "0xFF 0xZZ")
+	_, err = sc.GetIntArrayBase("MASKS", 16)
+	if err == nil || !strings.Contains(err.Error(), "MASKS element 1") {
+		t.Fatalf("expected error naming the key and element index, received: %v", err)
+	}
+}
+
+func TestServiceConfig_ParseTo_compute(t *testing.T) {
+	type TestConfig struct {
+		URL  string `config:"-,compute=https://{HOST}:{PORT}/{PATH}"`
+		Host string `config:"HOST"`
+		Port int    `config:"PORT"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "COMPUTE",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("COMPUTE_HOST", "example.com")
+	t.Setenv("COMPUTE_PATH", "api")
+
+	n := &TestConfig{Port: 8443}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n.URL != "https://example.com:8443/api" {
+		t.Fatalf("computed value is not the same with expectation, received: %s", n.URL)
+	}
+
+	type MissingConfig struct {
+		URL string `config:"-,compute={SCHEME}://{HOST}"`
+	}
+
+	err = sc.ParseTo(&MissingConfig{})
+	if !errors.Is(err, ErrConfigNotFound) || !strings.Contains(err.Error(), "SCHEME") {
+		t.Fatalf("expected not found error for the referenced key, received: %v", err)
+	}
+}
+
+func TestServiceConfig_GetStringMap(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "MAP",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("MAP_LABELS", "a=1  b=x=y a=3")
+	labels, err := sc.GetStringMap("LABELS")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]string{"a": "3", "b": "x=y"}
+	if !reflect.DeepEqual(expect, labels) {
+		t.Fatalf("parsed map is not the same with expectation, received: %v, expected: %v", labels, expect)
+	}
+
+	t.Setenv("MAP_EMPTY", "")
+	empty, err := sc.GetStringMap("EMPTY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(empty) != 0 {
+		t.Fatalf("expected empty map, received: %v", empty)
+	}
+
+	t.Setenv("MAP_INVALID", "a=1 b")
+	_, err = sc.GetStringMap("INVALID")
+	if err == nil || !strings.Contains(err.Error(), "INVALID") {
+		t.Fatalf("expected error naming the key for entry without \"=\", received: %v", err)
+	}
+
+	def := map[string]string{"default": "yes"}
+	m, err := sc.GetStringMapWithDefault("MISSING", def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(def, m) {
+		t.Fatalf("expected default map, received: %v", m)
+	}
+
+	type TestConfig struct {
+		Labels map[string]string `config:"LABELS"`
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expect, n.Labels) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n.Labels, expect)
+	}
+}
+
+func TestServiceConfig_ParseTo_json(t *testing.T) {
+	type Endpoint struct {
+		Name string `json:"name"`
+		Port int    `json:"port"`
+	}
+
+	type TestConfig struct {
+		Endpoints []Endpoint        `config:"ENDPOINTS,json"`
+		Labels    map[string]string `config:"LABELS,json"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "JSON",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("JSON_ENDPOINTS", `[{"name": "api", "port": 80}]`)
+	t.Setenv("JSON_LABELS", `{"a": "b"}`)
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := &TestConfig{
+		Endpoints: []Endpoint{{Name: "api", Port: 80}},
+		Labels:    map[string]string{"a": "b"},
+	}
+	if !reflect.DeepEqual(expect, n) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expect)
+	}
+
+	t.Setenv("JSON_LABELS", `["a", "b"]`)
+	err = sc.ParseTo(n)
+
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("expected error to unwrap to json.UnmarshalTypeError, received: %v", err)
+	}
+	if !strings.Contains(err.Error(), "JSON_LABELS") || !strings.Contains(err.Error(), "map[string]string") {
+		t.Fatalf("expected error naming the key and Go type, received: %v", err)
+	}
+}
+
+func TestServiceConfig_ParseToWithDefaults(t *testing.T) {
+	type TestConfig struct {
+		Host    string   `config:"HOST"`
+		Port    int      `config:"PORT"`
+		Workers int      `config:"WORKERS,default=4"`
+		Tags    []string `config:"TAGS"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "WITHDEF",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("WITHDEF_PORT", "9090")
+
+	defaults := &TestConfig{Host: "localhost", Port: 8080, Workers: 1, Tags: []string{"a"}}
+	n := &TestConfig{}
+	err := sc.ParseToWithDefaults(n, defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{Host: "localhost", Port: 9090, Workers: 4, Tags: []string{"a"}}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+	if defaults.Port != 8080 {
+		t.Fatalf("defaults must not be modified, received: %v", defaults)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for different types")
+		}
+	}()
+	_ = sc.ParseToWithDefaults(n, &struct{}{})
+}
+
+func TestServiceConfig_ZeroMissing(t *testing.T) {
+	type TestConfig struct {
+		Host    string   `config:"HOST"`
+		Port    int      `config:"PORT"`
+		Workers int      `config:"WORKERS,default=4"`
+		Tags    []string `config:"TAGS"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "ZEROMISSING",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("ZEROMISSING_PORT", "9090")
+
+	n := &TestConfig{Host: "localhost", Port: 8080, Workers: 1, Tags: []string{"a"}}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &TestConfig{Host: "localhost", Port: 9090, Workers: 4, Tags: []string{"a"}}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("expected missing fields to be kept by default, received: %v, expected: %v", n, expected)
+	}
+
+	sc.ZeroMissing = true
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = &TestConfig{Port: 9090, Workers: 4}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+}
+
+func TestServiceConfig_ParseTo_default(t *testing.T) {
+	type TestConfig struct {
+		URL  string `config:"URL,default=https://{HOST}:{PORT}"`
+		Host string `config:"HOST,default=localhost"`
+		Port int    `config:"PORT,default=8080"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "DEFAULT",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("DEFAULT_HOST", "example.com")
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := &TestConfig{
+		URL:  "https://example.com:8080",
+		Host: "example.com",
+		Port: 8080,
+	}
+	if !reflect.DeepEqual(expect, n) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expect)
+	}
+
+	type CycleConfig struct {
+		A string `config:"A,default={B}"`
+		B string `config:"B,default=x{A}"`
+	}
+
+	err = sc.ParseTo(&CycleConfig{})
+	if err == nil || !strings.Contains(err.Error(), "cycle in defaults: A -> B -> A") {
+		t.Fatalf("expected cycle error, received: %v", err)
+	}
+}
+
+func TestGetStringArrayMapped(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "MAPPED",
+		ArraySeparator: ",",
+	}
+
+	t.Setenv("MAPPED_NAMES", "a,b")
+	upper, err := GetStringArrayMapped(sc, "NAMES", func(s string) (string, error) {
+		return strings.ToUpper(s), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]string{"A", "B"}, upper) {
+		t.Fatalf("mapped array is not the same with expectation, received: %v", upper)
+	}
+
+	t.Setenv("MAPPED_RATIOS", "0.5,x")
+	_, err = GetStringArrayMapped(sc, "RATIOS", func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	})
+	if !errors.Is(err, strconv.ErrSyntax) || !strings.Contains(err.Error(), "MAPPED_RATIOS element 1") {
+		t.Fatalf("expected wrapped error with the key and element index, received: %v", err)
+	}
+}
+
+func TestServiceConfig_GetStringArrayFiltered(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "FILTERED",
+		ArraySeparator: ",",
+	}
+	notComment := func(s string) bool {
+		return !strings.HasPrefix(s, "#")
+	}
+
+	t.Setenv("FILTERED_HOSTS", "a,#b,c")
+	hosts, err := sc.GetStringArrayFiltered("HOSTS", notComment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]string{"a", "c"}, hosts) {
+		t.Fatalf("filtered array is not the same with expectation, received: %v", hosts)
+	}
+
+	t.Setenv("FILTERED_HOSTS", "#a,#b")
+	hosts, err = sc.GetStringArrayFiltered("HOSTS", notComment)
+	if err != nil || hosts == nil || len(hosts) != 0 {
+		t.Fatalf("expected an empty slice, received: %v, %v", hosts, err)
+	}
+
+	_, err = sc.GetStringArrayFiltered("MISSING", notComment)
+	if !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("expected ErrConfigNotFound, received: %v", err)
+	}
+}
+
+func TestServiceConfig_RangeStringArray(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "RANGE",
+		ArraySeparator: ", ",
+	}
+
+	t.Setenv("RANGE_HOSTS", "a, b, , c")
+
+	expect, err := sc.GetStringArray("HOSTS")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := make([]string, 0)
+	err = sc.RangeStringArray("HOSTS", func(s string) bool {
+		received = append(received, s)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expect, received) {
+		t.Fatalf("ranged elements are not the same with GetStringArray, received: %v, expected: %v", received, expect)
+	}
+
+	received = received[:0]
+	_ = sc.RangeStringArray("HOSTS", func(s string) bool {
+		received = append(received, s)
+		return s != "b"
+	})
+	if !reflect.DeepEqual([]string{"a", "b"}, received) {
+		t.Fatalf("expected ranging to stop after b, received: %v", received)
+	}
+
+	err = sc.RangeStringArray("MISSING", func(string) bool { return true })
+	if !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("expected ErrConfigNotFound, received: %v", err)
+	}
+}
+
+// testLevel is a log level decoded from its name with UnmarshalText, standing in for types such as slog.Level.
+type testLevel int
+
+const (
+	testLevelDebug testLevel = iota
+	testLevelInfo
+	testLevelWarn
+	testLevelError
+)
+
+var testLevelNames = []string{"DEBUG", "INFO", "WARN", "ERROR"}
+
+func (l testLevel) MarshalText() ([]byte, error) {
+	if l < 0 || int(l) >= len(testLevelNames) {
+		return nil, fmt.Errorf("unknown level %d", int(l))
+	}
+
+	return []byte(testLevelNames[l]), nil
+}
+
+func (l *testLevel) UnmarshalText(text []byte) error {
+	for i, name := range testLevelNames {
+		if strings.EqualFold(string(text), name) {
+			*l = testLevel(i)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown level %q", text)
+}
+
+func TestServiceConfig_ParseTo_textUnmarshaler(t *testing.T) {
+	type TestConfig struct {
+		Level      testLevel  `config:"LOG_LEVEL"`
+		AuditLevel *testLevel `config:"AUDIT_LEVEL"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "TEXT",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("TEXT_LOG_LEVEL", "warn")
+	t.Setenv("TEXT_AUDIT_LEVEL", "debug")
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Level != testLevelWarn || n.AuditLevel == nil || *n.AuditLevel != testLevelDebug {
+		t.Fatalf("unexpected decoded levels: %v, %v", n.Level, n.AuditLevel)
+	}
+
+	t.Setenv("TEXT_LOG_LEVEL", "verbose")
+	err = sc.ParseTo(n)
+	if err == nil || !strings.Contains(err.Error(), "TEXT_LOG_LEVEL") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+}
+
+func TestServiceConfig_ParseTo_textKeyMap(t *testing.T) {
+	type TestConfig struct {
+		Sampling map[testLevel]int           `config:"SAMPLING"`
+		Timeouts map[testLevel]time.Duration `config:"TIMEOUTS"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "TEXTMAP",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("TEXTMAP_SAMPLING", "debug=0 info=1 error=100")
+	t.Setenv("TEXTMAP_TIMEOUTS", "warn=5s")
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{
+		Sampling: map[testLevel]int{testLevelDebug: 0, testLevelInfo: 1, testLevelError: 100},
+		Timeouts: map[testLevel]time.Duration{testLevelWarn: 5 * time.Second},
+	}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+
+	t.Setenv("TEXTMAP_SAMPLING", "debug=0 verbose=1")
+	err = sc.ParseTo(n)
+	if err == nil || !strings.Contains(err.Error(), "TEXTMAP_SAMPLING") || !strings.Contains(err.Error(), "verbose=1") {
+		t.Fatalf("expected error naming the key and entry, received: %v", err)
+	}
+
+	t.Setenv("TEXTMAP_SAMPLING", "debug=x")
+	err = sc.ParseTo(n)
+	if err == nil || !strings.Contains(err.Error(), "debug=x") {
+		t.Fatalf("expected error naming the entry, received: %v", err)
+	}
+}
+
+func TestServiceConfig_ParseTo_scanner(t *testing.T) {
+	type TestConfig struct {
+		Name    sql.NullString `config:"NAME"`
+		Limit   sql.NullInt64  `config:"LIMIT"`
+		Timeout *sql.NullInt64 `config:"TIMEOUT"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "SCAN",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("SCAN_NAME", "orders")
+	t.Setenv("SCAN_LIMIT", "42")
+	t.Setenv("SCAN_TIMEOUT", "30")
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{
+		Name:    sql.NullString{String: "orders", Valid: true},
+		Limit:   sql.NullInt64{Int64: 42, Valid: true},
+		Timeout: &sql.NullInt64{Int64: 30, Valid: true},
+	}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+
+	t.Setenv("SCAN_LIMIT", "many")
+	err = sc.ParseTo(n)
+	if err == nil || !strings.Contains(err.Error(), "SCAN_LIMIT") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+}
+
+// jsonTags decodes either a JSON array of strings or a single JSON string.
+type jsonTags []string
+
+func (t *jsonTags) UnmarshalJSON(data []byte) error {
+	var single string
+	if json.Unmarshal(data, &single) == nil {
+		*t = jsonTags{single}
+		return nil
+	}
+
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+func TestServiceConfig_ParseTo_keepRaw(t *testing.T) {
+	type TestConfig struct {
+		Timeout    time.Duration `config:"TIMEOUT,keepraw"`
+		TimeoutRaw string
+		Retries    int `config:"RETRIES,keepraw,default=3"`
+		RetriesRaw string
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "KEEPRAW",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("KEEPRAW_TIMEOUT", "1m30s")
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{Timeout: 90 * time.Second, TimeoutRaw: "1m30s", Retries: 3, RetriesRaw: "3"}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+
+	type MissingRaw struct {
+		Timeout time.Duration `config:"TIMEOUT,keepraw"`
+	}
+	err = sc.ParseTo(&MissingRaw{})
+	if err == nil || !strings.Contains(err.Error(), "TimeoutRaw") {
+		t.Fatalf("expected error naming the companion field, received: %v", err)
+	}
+}
+
+func TestServiceConfig_ParseTo_jsonUnmarshaler(t *testing.T) {
+	type TestConfig struct {
+		Tags    jsonTags  `config:"TAGS"`
+		Single  jsonTags  `config:"SINGLE"`
+		Pointer *jsonTags `config:"POINTER"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "JSONU",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("JSONU_TAGS", `["a","b"]`)
+	t.Setenv("JSONU_SINGLE", "hello world")
+	t.Setenv("JSONU_POINTER", `"quoted"`)
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{
+		Tags:    jsonTags{"a", "b"},
+		Single:  jsonTags{"hello world"},
+		Pointer: &jsonTags{"quoted"},
+	}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+
+	t.Setenv("JSONU_TAGS", "42")
+	err = sc.ParseTo(n)
+	if err == nil || !strings.Contains(err.Error(), "JSONU_TAGS") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+}
+
+func TestServiceConfig_GetJSONArray(t *testing.T) {
+	type Endpoint struct {
+		Name string `json:"name"`
+		Port int    `json:"port"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "JSONARRAY",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("JSONARRAY_ENDPOINTS", `[{"name": "api", "port": 80}, {"name": "admin", "port": 8080}]`)
+
+	var endpoints []Endpoint
+	err := sc.GetJSONArray("ENDPOINTS", &endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []Endpoint{{Name: "api", Port: 80}, {Name: "admin", Port: 8080}}
+	if !reflect.DeepEqual(expect, endpoints) {
+		t.Fatalf("decoded array is not the same with expectation, received: %v, expected: %v", endpoints, expect)
+	}
+
+	t.Setenv("JSONARRAY_ENDPOINTS", `[{"name": "api", "port": "80"}]`)
+	err = sc.GetJSONArray("ENDPOINTS", &endpoints)
+	if err == nil || !strings.Contains(err.Error(), "JSONARRAY_ENDPOINTS") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+}
+
+func TestServiceConfig_ParseTo_pairs(t *testing.T) {
+	type TestConfig struct {
+		Headers map[string]string `config:"HEADERS,pairs"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "PAIRS",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("PAIRS_HEADERS", "Content-Type=application/json Accept=*/* X-Signature=a=b==")
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]string{"Content-Type": "application/json", "Accept": "*/*", "X-Signature": "a=b=="}
+	if !reflect.DeepEqual(expect, n.Headers) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n.Headers, expect)
+	}
+
+	for _, value := range []string{"Accept", "=value"} {
+		t.Setenv("PAIRS_HEADERS", value)
+		err = sc.ParseTo(n)
+		if err == nil || !strings.Contains(err.Error(), "PAIRS_HEADERS") {
+			t.Fatalf("expected error naming the key for malformed entry %q, received: %v", value, err)
+		}
+	}
+}
+
+func TestServiceConfig_GetBoolStrict(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "STRICTBOOL",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("STRICTBOOL_ENABLED", "true")
+	t.Setenv("STRICTBOOL_LOOSE", "1")
+
+	v, err := sc.GetBoolStrict("ENABLED")
+	if err != nil || !v {
+		t.Fatalf("expected true, received: %v, %v", v, err)
+	}
+
+	_, err = sc.GetBoolStrict("LOOSE")
+	if err == nil || !strings.Contains(err.Error(), "STRICTBOOL_LOOSE") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+
+	v, err = sc.GetBool("LOOSE")
+	if err != nil || !v {
+		t.Fatalf("expected tolerant parsing to accept 1, received: %v, %v", v, err)
+	}
+
+	type TestConfig struct {
+		Enabled bool `config:"ENABLED,strictbool"`
+		Loose   bool `config:"LOOSE"`
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil || !n.Enabled || !n.Loose {
+		t.Fatalf("unexpected result: %v, %v", n, err)
+	}
+
+	type StrictConfig struct {
+		Loose bool `config:"LOOSE,strictbool"`
+	}
+
+	err = sc.ParseTo(&StrictConfig{})
+	if err == nil || !strings.Contains(err.Error(), "STRICTBOOL_LOOSE") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+}
+
+func TestServiceConfig_ParseTo_encrypted(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "ENCRYPTED",
+		ArraySeparator: " ",
+		Decrypt: func(key, raw string) (string, error) {
+			if key != "ENCRYPTED_PASSWORD" {
+				return "", fmt.Errorf("unexpected key %s", key)
+			}
+			decoded, err := hex.DecodeString(raw)
+			return string(decoded), err
+		},
+	}
+
+	t.Setenv("ENCRYPTED_PASSWORD", hex.EncodeToString([]byte("hunter2")))
+	t.Setenv("ENCRYPTED_USER", "admin")
+
+	type TestConfig struct {
+		Password string `config:"PASSWORD,encrypted,secure"`
+		User     string `config:"USER"`
+	}
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{Password: "hunter2", User: "admin"}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+
+	t.Setenv("ENCRYPTED_PASSWORD", "not hex")
+	err = sc.ParseTo(n)
+	if err == nil || !strings.Contains(err.Error(), "ENCRYPTED_PASSWORD") || !strings.Contains(err.Error(), "cannot decrypt") {
+		t.Fatalf("expected decryption error naming the key, received: %v", err)
+	}
+
+	sc.Decrypt = nil
+	err = sc.ParseTo(n)
+	if err == nil || !strings.Contains(err.Error(), "ENCRYPTED_PASSWORD") {
+		t.Fatalf("expected error for missing hook, received: %v", err)
+	}
+}
+
+func TestServiceConfig_ParseTo_chunked(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "CHUNKED",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("CHUNKED_CERT_1", "-----BEGIN ")
+	t.Setenv("CHUNKED_CERT_2", "CERTIFICATE-----")
+	t.Setenv("CHUNKED_CERT_4", "ignored")
+	t.Setenv("CHUNKED_PORTS_1", "80 4")
+	t.Setenv("CHUNKED_PORTS_2", "43")
+
+	type TestConfig struct {
+		Cert    string `config:"CERT,chunked"`
+		Ports   []int  `config:"PORTS,chunked"`
+		Missing string `config:"MISSING,chunked,default=none"`
+	}
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{Cert: "-----BEGIN CERTIFICATE-----", Ports: []int{80, 443}, Missing: "none"}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+}
+
+func TestServiceConfig_ParseTo_chunkedEnvironment(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "CHUNKENV",
+		ArraySeparator: " ",
+		Environment:    "prod",
+	}
+
+	t.Setenv("CHUNKENV_PROD_CERT_1", "new1")
+	t.Setenv("CHUNKENV_CERT_1", "old1")
+	t.Setenv("CHUNKENV_CERT_2", "old2")
+	t.Setenv("CHUNKENV_KEY_1", "base1")
+	t.Setenv("CHUNKENV_KEY_2", "base2")
+
+	type TestConfig struct {
+		Cert string `config:"CERT,chunked"`
+		Key  string `config:"KEY,chunked"`
+	}
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{Cert: "new1", Key: "base1base2"}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+
+	s, err := sc.Capture(&TestConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := &TestConfig{}
+	err = sc.Restore(restored, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(restored, expected) {
+		t.Fatalf("restored config is not the same with expectation, received: %v, expected: %v", restored, expected)
+	}
+}
+
+func TestServiceConfig_Prefix_interpolation(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "INTERP_${INTERP_TEST_REGION}",
+		ArraySeparator: " ",
+	}
+
+	_, err := sc.GetString("PORT")
+	if err == nil || !strings.Contains(err.Error(), "INTERP_TEST_REGION") {
+		t.Fatalf("expected error naming the missing variable, received: %v", err)
+	}
+
+	t.Setenv("INTERP_TEST_REGION", "EU")
+	t.Setenv("INTERP_EU_PORT", "8080")
+
+	port, err := sc.GetInt("PORT")
+	if err != nil || port != 8080 {
+		t.Fatalf("expected value from the interpolated prefix, received: %v, %v", port, err)
+	}
+
+	type TestConfig struct {
+		Port int    `config:"PORT"`
+		Host string `config:"HOST,required"`
+	}
+
+	err = sc.ParseTo(&TestConfig{})
+	var missing *MissingConfigError
+	if !errors.As(err, &missing) || missing.Keys[0] != "INTERP_EU_HOST" {
+		t.Fatalf("expected missing key with the interpolated prefix, received: %v", err)
+	}
+
+	t.Setenv("INTERP_EU_COUNT", "x")
+	type CountConfig struct {
+		Count int `config:"COUNT,default=1"`
+	}
+	err = sc.ParseTo(&CountConfig{})
+	if err == nil || !strings.Contains(err.Error(), "cannot parse INTERP_EU_COUNT:") {
+		t.Fatalf("expected error naming the expanded key, received: %v", err)
+	}
+
+	t.Setenv("INTERP_TEST_REGION", "")
+	t.Setenv("INTERP__COUNT", "2")
+	for _, parse := range []func() error{
+		func() error { return sc.ParseTo(&CountConfig{}) },
+		func() error { return sc.CheckRequired(&TestConfig{}) },
+		func() error { return sc.ApplyChanges(&CountConfig{}, map[string]string{"INTERP__COUNT": "3"}) },
+		func() error { _, err := sc.GetInt("COUNT"); return err },
+	} {
+		err = parse()
+		if err == nil || !strings.Contains(err.Error(), "INTERP_TEST_REGION") {
+			t.Fatalf("expected error naming the empty variable, received: %v", err)
+		}
+	}
+}
+
+func TestServiceConfig_ParseTo_onField(t *testing.T) {
+	received := make(map[string]interface{})
+	sc := ServiceConfig{
+		Prefix:         "ONFIELD",
+		ArraySeparator: " ",
+		OnField: func(key string, value interface{}) {
+			received[key] = value
+		},
+	}
+
+	t.Setenv("ONFIELD_PORT", "8080")
+	t.Setenv("ONFIELD_PASSWORD", "hunter2")
+
+	type TestConfig struct {
+		Port     int    `config:"PORT"`
+		Host     string `config:"HOST,default=localhost"`
+		Password string `config:"PASSWORD,secure"`
+		Unset    string `config:"UNSET"`
+		URL      string `config:"-,compute={HOST}:{PORT}"`
+	}
+
+	err := sc.ParseTo(&TestConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"PORT":     8080,
+		"HOST":     "localhost",
+		"PASSWORD": "********",
+		"URL":      "localhost:8080",
+	}
+	if !reflect.DeepEqual(received, expected) {
+		t.Fatalf("hook payloads are not the same with expectation, received: %v, expected: %v", received, expected)
+	}
+}
+
+func TestServiceConfig_ParseTo_environmentDefault(t *testing.T) {
+	type TestConfig struct {
+		Port int    `config:"PORT,default_production=443,default_development=8080,default=80"`
+		Host string `config:"HOST,default_production=example.com,default_func=hostname"`
+		URL  string `config:"URL,default_production=https://{HOST}:{PORT}"`
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		environment string
+		expected    TestConfig
+	}{
+		{"production", TestConfig{Port: 443, Host: "example.com", URL: "https://example.com:443"}},
+		{"Development", TestConfig{Port: 8080, Host: hostname}},
+		{"", TestConfig{Port: 80, Host: hostname}},
+	}
+
+	for _, c := range cases {
+		sc := ServiceConfig{
+			Prefix:         "ENVDEFAULT",
+			ArraySeparator: " ",
+			Environment:    c.environment,
+		}
+
+		n := &TestConfig{}
+		err := sc.ParseTo(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *n != c.expected {
+			t.Fatalf("decoded config is not the same with expectation for %q, received: %v, expected: %v", c.environment, *n, c.expected)
+		}
+	}
+
+	t.Setenv("ENVDEFAULT_PORT", "9090")
+
+	n := &TestConfig{}
+	err = ServiceConfig{Prefix: "ENVDEFAULT", Environment: "production"}.ParseTo(n)
+	if err != nil || n.Port != 9090 {
+		t.Fatalf("expected configured value to win over defaults, received: %v, %v", n.Port, err)
+	}
+}
+
+func TestServiceConfig_ParseTo_listOfMaps(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "MAPLIST",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("MAPLIST_ENDPOINTS", `[{"name": "primary", "url": "https://a"}, {"name": "backup", "url": "https://b"}]`)
+	t.Setenv("MAPLIST_LIMITS", `[{"name": "api", "rate": 10, "burst": true}]`)
+
+	type TestConfig struct {
+		Endpoints []map[string]string      `config:"ENDPOINTS,json"`
+		Named     []map[string]string      `config:"ENDPOINTS"`
+		Limits    []map[string]interface{} `config:"LIMITS"`
+	}
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endpoints := []map[string]string{
+		{"name": "primary", "url": "https://a"},
+		{"name": "backup", "url": "https://b"},
+	}
+	expected := &TestConfig{
+		Endpoints: endpoints,
+		Named:     endpoints,
+		Limits:    []map[string]interface{}{{"name": "api", "rate": float64(10), "burst": true}},
+	}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+
+	t.Setenv("MAPLIST_LIMITS", `[{"name": }]`)
+	err = sc.ParseTo(n)
+	if err == nil || !strings.Contains(err.Error(), "MAPLIST_LIMITS") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+}
+
+func TestServiceConfig_ArrayWithDefault(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "ARRAYDEF",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("ARRAYDEF_EMPTY", "")
+	t.Setenv("ARRAYDEF_STRINGS", "a b")
+	t.Setenv("ARRAYDEF_INTS", "1 2")
+	t.Setenv("ARRAYDEF_IPS", "10.0.0.1 ::1")
+	t.Setenv("ARRAYDEF_INVALID", "1 x")
+
+	get := map[string]func(name string) (interface{}, error){
+		"string": func(name string) (interface{}, error) {
+			return sc.GetStringArrayWithDefault(name, []string{"default"})
+		},
+		"int": func(name string) (interface{}, error) {
+			return sc.GetIntArrayWithDefault(name, []int{-1})
+		},
+		"ip": func(name string) (interface{}, error) {
+			return sc.GetIPArrayWithDefault(name, []net.IP{net.IPv4zero})
+		},
+	}
+
+	cases := []struct {
+		get      string
+		name     string
+		expected interface{}
+	}{
+		{"string", "UNSET", []string{"default"}},
+		{"string", "EMPTY", []string{}},
+		{"string", "STRINGS", []string{"a", "b"}},
+		{"int", "UNSET", []int{-1}},
+		{"int", "EMPTY", []int{}},
+		{"int", "INTS", []int{1, 2}},
+		{"ip", "UNSET", []net.IP{net.IPv4zero}},
+		{"ip", "EMPTY", []net.IP{}},
+		{"ip", "IPS", []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("::1")}},
+	}
+
+	for _, c := range cases {
+		v, err := get[c.get](c.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(v, c.expected) {
+			t.Fatalf("unexpected %s array for %s, received: %v, expected: %v", c.get, c.name, v, c.expected)
+		}
+	}
+
+	_, err := sc.GetIntArrayWithDefault("INVALID", []int{-1})
+	if err == nil || !strings.Contains(err.Error(), "INVALID") {
+		t.Fatalf("expected a parse error, received: %v", err)
+	}
+}
+
+func TestServiceConfig_StripQuotes(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "STRIPQUOTES",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("STRIPQUOTES_DOUBLE", `"true"`)
+	t.Setenv("STRIPQUOTES_SINGLE", `'false'`)
+	t.Setenv("STRIPQUOTES_PLAIN", "true")
+	t.Setenv("STRIPQUOTES_MISMATCHED", `"true'`)
+	t.Setenv("STRIPQUOTES_NAME", `"say "hi""`)
+	t.Setenv("STRIPQUOTES_PORT", `'8080'`)
+
+	_, err := sc.GetBool("DOUBLE")
+	if err == nil {
+		t.Fatal("expected quoted boolean to be an error without StripQuotes")
+	}
+
+	sc.StripQuotes = true
+	cases := []struct {
+		name     string
+		expected bool
+	}{
+		{"DOUBLE", true},
+		{"SINGLE", false},
+		{"PLAIN", true},
+	}
+	for _, c := range cases {
+		v, err := sc.GetBool(c.name)
+		if err != nil || v != c.expected {
+			t.Fatalf("unexpected result for %s, received: %v, %v", c.name, v, err)
+		}
+	}
+
+	_, err = sc.GetBool("MISMATCHED")
+	if err == nil {
+		t.Fatal("expected mismatched quotes to be kept")
+	}
+
+	type TestConfig struct {
+		Name string `config:"NAME"`
+		Port int    `config:"PORT"`
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &TestConfig{Name: `say "hi"`, Port: 8080}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Source provides config values from somewhere other than, or in addition to, the process environment, such as a
+// file or a remote service. See ServiceConfig.Sources.
+//
+// Keys given to a Source are the full names as they would appear in the environment, including the prefix, e.g.
+// "MYAPP_PORT".
+type Source interface {
+	// Lookup returns the value of key. A key that the source does not have is reported with found set to false and
+	// a nil error, while err is reserved for failures in reading the source itself.
+	Lookup(key string) (value string, found bool, err error)
+}
+
+// A WatchableSource is a Source that notifies when its values change, so that services can reload configs without
+// polling. See ServiceConfig.OnChange.
+type WatchableSource interface {
+	Source
+	// OnChange registers fn to be called with the key of every value that is added, modified or removed. fn may be
+	// called from another goroutine.
+	OnChange(fn func(key string))
+}
+
+// A ContextSource is a Source whose lookups can be cancelled or time-bounded with a context, such as a remote service.
+// See ServiceConfig.ParseToCtx.
+type ContextSource interface {
+	Source
+	// LookupContext is like Lookup, but gives up when ctx is done, returning an error wrapping ctx.Err().
+	LookupContext(ctx context.Context, key string) (value string, found bool, err error)
+}
+
+// sourceLookup looks key up in source, through LookupContext when ctx is not nil and source is a ContextSource.
+func sourceLookup(ctx context.Context, source Source, key string) (string, bool, error) {
+	if cs, ok := source.(ContextSource); ok && ctx != nil {
+		return cs.LookupContext(ctx, key)
+	}
+
+	return source.Lookup(key)
+}
+
+// OnChange registers fn with every WatchableSource in Sources. Sources that cannot be watched are ignored.
+func (sc ServiceConfig) OnChange(fn func(key string)) {
+	for _, source := range sc.Sources {
+		if w, ok := source.(WatchableSource); ok {
+			w.OnChange(fn)
+		}
+	}
+}
+
+// EnvSource is a Source that reads from the process environment.
+type EnvSource struct{}
+
+func (EnvSource) Lookup(key string) (string, bool, error) {
+	value, found := os.LookupEnv(key)
+	return value, found, nil
+}
+
+func (EnvSource) String() string {
+	return ProvenanceEnv
+}
+
+// sourceName returns the name of source as reported by ParseToWithProvenance, which is its String method if it has
+// one, or its type otherwise.
+func sourceName(source Source) string {
+	if s, ok := source.(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	return fmt.Sprintf("%T", source)
+}
+
+// MapSource is a Source backed by a map of keys to values.
+type MapSource map[string]string
+
+func (m MapSource) Lookup(key string) (string, bool, error) {
+	value, found := m[key]
+	return value, found, nil
+}
+
+// NewArgsSource returns a Source built from KEY=VALUE pairs, such as the positional arguments of a command, e.g.
+// os.Args[1:]. Keys are used as given, so they must include the prefix, e.g. "MYAPP_PORT=8080". When a key is given
+// more than once, the last value wins.
+//
+// An argument without "=", or with an empty key, is an error, unless skipMalformed is set, in which case it is
+// ignored. To give the arguments precedence over the environment, put the Source before an EnvSource in Sources.
+func NewArgsSource(args []string, skipMalformed bool) (Source, error) {
+	m := make(MapSource, len(args))
+	for i, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			if skipMalformed {
+				continue
+			}
+			return nil, fmt.Errorf("argument %d (%q) is not in KEY=VALUE form", i, arg)
+		}
+
+		m[key] = value
+	}
+
+	return m, nil
+}
+
+// NewJSONSource reads the JSON document in the file at path and returns it as a Source. The document must be an
+// object, and it is flattened using Flatten, with arrays joined by arraySeparator.
+func NewJSONSource(path string, arraySeparator string) (Source, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	doc := make(map[string]interface{})
+	decoder := json.NewDecoder(f)
+	decoder.UseNumber()
+	err = decoder.Decode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s: %w", path, err)
+	}
+
+	return MapSource(Flatten(doc, arraySeparator)), nil
+}
+
+// Flatten converts a nested document, such as one decoded from JSON, YAML or TOML into a map[string]interface{},
+// into flat keys consumable by getters. Keys of nested objects are joined with "_" and upper-cased, so that
+// {"myapp": {"db": {"host": "localhost"}}} is flattened into MYAPP_DB_HOST=localhost.
+//
+// Arrays of scalar values are joined with arraySeparator, to be read back with getters such as GetStringArray.
+// Arrays containing objects are flattened with the element index as a key instead, e.g. MYAPP_SERVERS_0_HOST.
+func Flatten(doc map[string]interface{}, arraySeparator string) map[string]string {
+	flat := make(map[string]string)
+	flattenInto(flat, "", doc, arraySeparator)
+	return flat
+}
+
+func flattenInto(flat map[string]string, key string, value interface{}, arraySeparator string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			flattenInto(flat, joinFlatKey(key, k), child, arraySeparator)
+		}
+	case []map[string]interface{}:
+		for i, child := range v {
+			flattenInto(flat, joinFlatKey(key, strconv.Itoa(i)), child, arraySeparator)
+		}
+	case []interface{}:
+		scalars := make([]string, 0, len(v))
+		for i, child := range v {
+			switch child.(type) {
+			case map[string]interface{}, []interface{}:
+				flattenInto(flat, joinFlatKey(key, strconv.Itoa(i)), child, arraySeparator)
+			default:
+				scalars = append(scalars, formatScalar(child))
+			}
+		}
+
+		if len(scalars) == len(v) {
+			flat[key] = strings.Join(scalars, arraySeparator)
+		}
+	default:
+		flat[key] = formatScalar(v)
+	}
+}
+
+func joinFlatKey(parent, child string) string {
+	child = strings.ToUpper(child)
+	if parent == "" {
+		return child
+	}
+
+	return parent + "_" + child
+}
+
+// formatScalar formats a decoded document value the way it would be written in an environment variable.
+func formatScalar(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
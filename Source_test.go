@@ -0,0 +1,162 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestServiceConfig_Sources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(path, []byte(`{"sources": {"port": 8080, "host": "file", "tags": ["a", "b"], "db": {"name": "app"}}}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jsonSource, err := NewJSONSource(path, " ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SOURCES_HOST", "env")
+
+	type TestConfig struct {
+		Port   int      `config:"PORT"`
+		Host   string   `config:"HOST"`
+		Tags   []string `config:"TAGS"`
+		DBName string   `config:"DB_NAME"`
+		Mode   string   `config:"MODE"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "SOURCES",
+		ArraySeparator: " ",
+		Sources:        []Source{MapSource{"SOURCES_MODE": "args"}, EnvSource{}, jsonSource},
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := &TestConfig{
+		Port:   8080,
+		Host:   "env",
+		Tags:   []string{"a", "b"},
+		DBName: "app",
+		Mode:   "args",
+	}
+	if !reflect.DeepEqual(expect, n) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expect)
+	}
+}
+
+func TestNewArgsSource(t *testing.T) {
+	args, err := NewArgsSource([]string{"ARGS_PORT=9090", "ARGS_URL=http://a?b=c"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "ARGS",
+		ArraySeparator: " ",
+		Sources:        []Source{args, EnvSource{}},
+	}
+
+	t.Setenv("ARGS_PORT", "8080")
+	t.Setenv("ARGS_HOST", "env")
+
+	type TestConfig struct {
+		Port int    `config:"PORT"`
+		Host string `config:"HOST"`
+		URL  string `config:"URL"`
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{Port: 9090, Host: "env", URL: "http://a?b=c"}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+
+	_, err = NewArgsSource([]string{"ARGS_PORT=9090", "verbose"}, false)
+	if err == nil {
+		t.Fatal("expected error for malformed argument")
+	}
+
+	skipped, err := NewArgsSource([]string{"verbose", "=x", "ARGS_PORT=9090"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(skipped, MapSource{"ARGS_PORT": "9090"}) {
+		t.Fatalf("unexpected source: %v", skipped)
+	}
+}
+
+func TestServiceConfig_LookupFunc(t *testing.T) {
+	values := map[string]string{
+		"LOOKUPFUNC_EU_PORT":  "8080",
+		"LOOKUPFUNC_EU_HOSTS": "a b",
+		"REGION":              "EU",
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "LOOKUPFUNC_${REGION}",
+		ArraySeparator: " ",
+		LookupFunc: func(key string) (string, bool) {
+			value, ok := values[key]
+			return value, ok
+		},
+	}
+
+	t.Setenv("REGION", "US")
+	t.Setenv("LOOKUPFUNC_EU_NAME", "from-env")
+
+	port, err := sc.GetInt("PORT")
+	if err != nil || port != 8080 {
+		t.Fatalf("expected the port from LookupFunc, received: %v, %v", port, err)
+	}
+
+	type TestConfig struct {
+		Port  int      `config:"PORT"`
+		Hosts []string `config:"HOSTS"`
+		Name  string   `config:"NAME,default=fallback"`
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := &TestConfig{Port: 8080, Hosts: []string{"a", "b"}, Name: "fallback"}
+	if !reflect.DeepEqual(expect, n) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expect)
+	}
+
+	sc.Sources = []Source{MapSource{"LOOKUPFUNC_EU_NAME": "from-map"}, EnvSource{}}
+	n = &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect = &TestConfig{Port: 8080, Hosts: []string{"a", "b"}, Name: "from-map"}
+	if !reflect.DeepEqual(expect, n) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expect)
+	}
+
+	err = sc.SetDefault("PORT", "9090")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, exist := os.LookupEnv("LOOKUPFUNC_EU_PORT"); exist {
+		t.Fatal("expected SetDefault to keep the config provided by LookupFunc")
+	}
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"reflect"
+)
+
+// ApplyChanges updates the fields of the struct pointed by obj, usually filled by ParseTo before, whose configs are
+// in changes, e.g. to reload incrementally on the events of a WatchableSource. The keys of changes are full config
+// names including the prefix, as given to OnChange functions, and their values are parsed like ParseTo would parse
+// them. When Environment is set, the overlay name of a field is preferred over its base name.
+//
+// Fields whose configs are not in changes are left untouched, including computed fields, while the values of the
+// others are replaced, e.g. a map decoded with the `json` option is not merged into the previous one. The update is
+// atomic: when any value cannot be parsed, an error is returned and obj is not modified.
+func (sc ServiceConfig) ApplyChanges(obj interface{}, changes map[string]string) error {
+	assertPointer(obj)
+	sc = sc.withStructSettings(obj)
+	_, err := sc.expandPrefix()
+	if err != nil {
+		return err
+	}
+
+	target := reflect.ValueOf(obj).Elem()
+	updated := reflect.New(target.Type())
+	updated.Elem().Set(target)
+
+	for _, f := range configFields(updated.Interface()) {
+		if f.name == "" || f.name == "-" {
+			continue
+		}
+
+		configData, ok := changes[sc.getConfigName(f.name)]
+		if sc.Environment != "" {
+			if overlay, found := changes[sc.getConfigName(sc.environmentName(f.name))]; found {
+				configData, ok = overlay, true
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		fsc := sc.withOptions(f.opts.getOptions())
+		configData, _ = fsc.clean(configData, true)
+
+		if f.opts.has("encrypted") {
+			configData, err = sc.decrypt(f.name, configData)
+			if err != nil {
+				return sc.reformatParseError(f.name, err)
+			}
+		}
+
+		// The copy of the struct shares maps, slices and pointers with obj, so values are decoded into a fresh zero
+		// value rather than into the one held by obj, which would modify obj even when a later change fails.
+		f.value.Set(reflect.Zero(f.value.Type()))
+		err = fsc.setField(f.value, f.name, configData, f.opts)
+		if err != nil {
+			return sc.reformatParseError(f.name, err)
+		}
+		err = f.setRaw(configData)
+		if err != nil {
+			return sc.reformatParseError(f.name, err)
+		}
+		err = validateField(f)
+		if err != nil {
+			return sc.reformatParseError(f.name, err)
+		}
+	}
+
+	target.Set(updated.Elem())
+	return nil
+}
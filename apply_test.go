@@ -0,0 +1,85 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServiceConfig_ApplyChanges(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "APPLY",
+		ArraySeparator: " ",
+	}
+
+	type TestConfig struct {
+		Port  int      `config:"PORT,max=65535"`
+		Host  string   `config:"HOST"`
+		Hosts []string `config:"HOSTS"`
+	}
+
+	n := &TestConfig{Port: 8080, Host: "localhost", Hosts: []string{"a"}}
+	err := sc.ApplyChanges(n, map[string]string{
+		"APPLY_PORT":  "9090",
+		"APPLY_HOSTS": "b c",
+		"OTHER_HOST":  "ignored",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{Port: 9090, Host: "localhost", Hosts: []string{"b", "c"}}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+
+	err = sc.ApplyChanges(n, map[string]string{
+		"APPLY_HOST": "example.com",
+		"APPLY_PORT": "70000",
+	})
+	if err == nil || !strings.Contains(err.Error(), "APPLY_PORT") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("expected no change after a failed update, received: %v", n)
+	}
+}
+
+func TestServiceConfig_ApplyChanges_shared(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "APPLYSHARED",
+		ArraySeparator: " ",
+	}
+
+	type TestConfig struct {
+		Limits map[string]int `config:"LIMITS,json"`
+		Hosts  []string       `config:"HOSTS,json"`
+		Port   int            `config:"PORT"`
+	}
+
+	hosts := []string{"a", "b"}
+	n := &TestConfig{Limits: map[string]int{"a": 1}, Hosts: hosts, Port: 80}
+	err := sc.ApplyChanges(n, map[string]string{
+		"APPLYSHARED_LIMITS": `{"b": 2}`,
+		"APPLYSHARED_HOSTS":  `["c", "d"]`,
+		"APPLYSHARED_PORT":   "eighty",
+	})
+	if err == nil {
+		t.Fatal("expected error for an invalid port")
+	}
+
+	expected := &TestConfig{Limits: map[string]int{"a": 1}, Hosts: []string{"a", "b"}, Port: 80}
+	if !reflect.DeepEqual(n, expected) || !reflect.DeepEqual(hosts, []string{"a", "b"}) {
+		t.Fatalf("expected the config to be unchanged after an error, received: %v, expected: %v", n, expected)
+	}
+
+	err = sc.ApplyChanges(n, map[string]string{"APPLYSHARED_LIMITS": `{"b": 2}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected.Limits = map[string]int{"b": 2}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+}
@@ -0,0 +1,116 @@
+// Package azurekeyvault provides a config.Source reading secrets from Azure Key Vault. It is kept in its own module
+// so that the Azure dependencies are only required by services that use it.
+package azurekeyvault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	config "github.com/potatobeansco/go-config"
+)
+
+// SecretGetter is the part of the Key Vault secrets client used by the source. It is satisfied by
+// *azsecrets.Client.
+type SecretGetter interface {
+	GetSecret(ctx context.Context, name string, version string, options *azsecrets.GetSecretOptions) (azsecrets.GetSecretResponse, error)
+}
+
+// ErrAccess is wrapped by errors returned when a secret cannot be read for any other reason than not existing, e.g.
+// because of missing permissions or a disabled secret. Secrets that do not exist are not errors, but are reported
+// as not found.
+var ErrAccess = errors.New("cannot access secret")
+
+// Source is a config.Source that resolves each config key to a secret of a vault.
+type Source struct {
+	client   SecretGetter
+	vaultURL string
+
+	mu    sync.Mutex
+	cache map[string]entry
+}
+
+type entry struct {
+	value string
+	found bool
+}
+
+// NewAzureKeyVaultSource returns a Source reading secrets through client, which must be created for the vault at
+// vaultURL, e.g. "https://myvault.vault.azure.net/".
+//
+// Since secret names may only contain alphanumeric characters and dashes, a config key is resolved to the secret
+// named as the key with every "_" replaced by "-", e.g. the key MYAPP_DB_PASSWORD to the secret MYAPP-DB-PASSWORD.
+// The latest version is read, unless a version is pinned by appending it to the config name after "@", e.g.
+// `config:"DB_PASSWORD@0123abcd"`. Results, including missing secrets, are cached until Refresh is called.
+func NewAzureKeyVaultSource(client SecretGetter, vaultURL string) (config.Source, error) {
+	if client == nil {
+		return nil, errors.New("azurekeyvault: client is nil")
+	}
+	if vaultURL == "" {
+		return nil, errors.New("azurekeyvault: vault URL is empty")
+	}
+
+	return &Source{
+		client:   client,
+		vaultURL: vaultURL,
+		cache:    make(map[string]entry),
+	}, nil
+}
+
+func (s *Source) Lookup(key string) (string, bool, error) {
+	return s.LookupContext(context.Background(), key)
+}
+
+// LookupContext is like Lookup, but the request to the vault is cancelled when ctx is done.
+func (s *Source) LookupContext(ctx context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	e, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok {
+		return e.value, e.found, nil
+	}
+
+	name, version, _ := strings.Cut(key, "@")
+	name = strings.ReplaceAll(name, "_", "-")
+
+	resp, err := s.client.GetSecret(ctx, name, version, nil)
+	var respErr *azcore.ResponseError
+	switch {
+	case err != nil && ctx.Err() != nil:
+		return "", false, fmt.Errorf("azurekeyvault: cannot read %s: %w", key, ctx.Err())
+	case errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound:
+		e = entry{}
+	case err != nil:
+		return "", false, fmt.Errorf("%w %s: %w", ErrAccess, key, err)
+	case resp.Value == nil:
+		e = entry{found: true}
+	default:
+		e = entry{value: *resp.Value, found: true}
+	}
+
+	s.mu.Lock()
+	s.cache[key] = e
+	s.mu.Unlock()
+
+	return e.value, e.found, nil
+}
+
+// Refresh clears the cache, so that every secret is read again from the vault on its next lookup.
+func (s *Source) Refresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cache = make(map[string]entry)
+}
+
+// String returns the name of the source, used when reporting where a config came from.
+func (s *Source) String() string {
+	return "azurekeyvault:" + s.vaultURL
+}
+
+var _ config.ContextSource = (*Source)(nil)
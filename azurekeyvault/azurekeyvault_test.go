@@ -0,0 +1,114 @@
+package azurekeyvault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	config "github.com/potatobeansco/go-config"
+)
+
+type fakeGetter struct {
+	secrets map[string]string
+	calls   int
+}
+
+func (f *fakeGetter) GetSecret(ctx context.Context, name string, version string, _ *azsecrets.GetSecretOptions) (azsecrets.GetSecretResponse, error) {
+	f.calls++
+	if name == "MYAPP-SLOW" {
+		<-ctx.Done()
+		return azsecrets.GetSecretResponse{}, ctx.Err()
+	}
+	if name == "MYAPP-FORBIDDEN" {
+		return azsecrets.GetSecretResponse{}, &azcore.ResponseError{StatusCode: http.StatusForbidden, ErrorCode: "Forbidden"}
+	}
+
+	value, ok := f.secrets[name+"@"+version]
+	if !ok {
+		return azsecrets.GetSecretResponse{}, &azcore.ResponseError{StatusCode: http.StatusNotFound, ErrorCode: "SecretNotFound"}
+	}
+
+	return azsecrets.GetSecretResponse{Secret: azsecrets.Secret{Value: &value}}, nil
+}
+
+func TestNewAzureKeyVaultSource(t *testing.T) {
+	getter := &fakeGetter{secrets: map[string]string{
+		"MYAPP-DB-PASSWORD@":         "latest",
+		"MYAPP-DB-PASSWORD@0123abcd": "pinned",
+	}}
+
+	source, err := NewAzureKeyVaultSource(getter, "https://myvault.vault.azure.net/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc := config.ServiceConfig{
+		Prefix:         "MYAPP",
+		ArraySeparator: " ",
+		Sources:        []config.Source{source},
+	}
+
+	type TestConfig struct {
+		Password       string `config:"DB_PASSWORD"`
+		PinnedPassword string `config:"DB_PASSWORD@0123abcd"`
+		Missing        string `config:"MISSING"`
+	}
+
+	n := &TestConfig{Missing: "default"}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := TestConfig{Password: "latest", PinnedPassword: "pinned", Missing: "default"}
+	if *n != expected {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", *n, expected)
+	}
+
+	calls := getter.calls
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if getter.calls != calls {
+		t.Fatalf("expected cached lookups, received %d calls instead of %d", getter.calls, calls)
+	}
+
+	getter.secrets["MYAPP-DB-PASSWORD@"] = "rotated"
+	source.(*Source).Refresh()
+
+	password, err := sc.GetString("DB_PASSWORD")
+	if err != nil || password != "rotated" {
+		t.Fatalf("expected refreshed secret, received: %v, %v", password, err)
+	}
+
+	_, err = sc.GetString("FORBIDDEN")
+	if !errors.Is(err, ErrAccess) {
+		t.Fatalf("expected ErrAccess, received: %v", err)
+	}
+}
+
+func TestSource_LookupContext(t *testing.T) {
+	source, err := NewAzureKeyVaultSource(&fakeGetter{}, "https://myvault.vault.azure.net/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc := config.ServiceConfig{
+		Prefix:  "MYAPP",
+		Sources: []config.Source{source},
+	}
+
+	type TestConfig struct {
+		Password string `config:"SLOW,timeout=10ms"`
+	}
+
+	err = sc.ParseToCtx(context.Background(), &TestConfig{})
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "MYAPP_SLOW") {
+		t.Fatalf("expected a deadline error naming the key, received: %v", err)
+	}
+}
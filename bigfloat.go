@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// GetBigFloat returns the config value parsed as a *big.Float with big.Float.SetString, e.g. for rates that must not
+// be rounded to a float64. The precision is 64 bits; see GetBigFloatPrec for more.
+func (sc ServiceConfig) GetBigFloat(name string, opts ...GetOption) (*big.Float, error) {
+	return sc.GetBigFloatPrec(name, 0, opts...)
+}
+
+// GetBigFloatPrec is like GetBigFloat, but the value is parsed with a precision of prec bits. A prec of 0 means 64.
+// ParseTo does the same for *big.Float fields tagged with the `prec` option, e.g. `config:"RATE,prec=200"`.
+func (sc ServiceConfig) GetBigFloatPrec(name string, prec uint, opts ...GetOption) (*big.Float, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+
+	f, err := sc.parseBigFloat(configData, prec)
+	if err != nil {
+		return nil, sc.reformatParseError(name, err)
+	}
+	return f, nil
+}
+
+func (sc ServiceConfig) GetBigFloatWithDefault(name string, defaultValue *big.Float, opts ...GetOption) (*big.Float, error) {
+	f, err := sc.GetBigFloat(name, opts...)
+	if errors.Is(err, ErrConfigNotFound) {
+		return defaultValue, nil
+	}
+	return f, err
+}
+
+// parseBigFloat parses s as a *big.Float with a precision of prec bits, after removing NumberGrouping.
+func (sc ServiceConfig) parseBigFloat(s string, prec uint) (*big.Float, error) {
+	f, ok := new(big.Float).SetPrec(prec).SetString(sc.ungroup(s))
+	if !ok {
+		return nil, fmt.Errorf("invalid number %q", s)
+	}
+
+	return f, nil
+}
+
+// parsePrec parses the `prec` option of a *big.Float field.
+func parsePrec(opts tagOptions) (uint, error) {
+	p, ok := opts.get("prec")
+	if !ok {
+		return 0, nil
+	}
+
+	prec, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid prec option `%s`: %w", p, err)
+	}
+
+	return uint(prec), nil
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestServiceConfig_GetBigFloat(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "BIGFLOAT",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("BIGFLOAT_RATE", "0.1")
+	rate, err := sc.GetBigFloat("RATE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.Prec() != 64 || rate.Text('g', 10) != "0.1" {
+		t.Fatalf("unexpected value %s with precision %d", rate.Text('g', 10), rate.Prec())
+	}
+
+	rate, err = sc.GetBigFloatPrec("RATE", 200)
+	if err != nil || rate.Prec() != 200 {
+		t.Fatalf("expected precision 200, received: %v, %v", rate, err)
+	}
+
+	def := big.NewFloat(1.5)
+	rate, err = sc.GetBigFloatWithDefault("MISSING", def)
+	if err != nil || rate != def {
+		t.Fatalf("expected the default, received: %v, %v", rate, err)
+	}
+
+	t.Setenv("BIGFLOAT_RATE", "ten")
+	_, err = sc.GetBigFloat("RATE")
+	if err == nil || !strings.Contains(err.Error(), "BIGFLOAT_RATE") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+
+	_, err = sc.GetBigFloat("MISSING")
+	if !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("expected ErrConfigNotFound, received: %v", err)
+	}
+}
+
+func TestServiceConfig_ParseTo_bigFloat(t *testing.T) {
+	type TestConfig struct {
+		Rate    *big.Float `config:"RATE"`
+		Precise *big.Float `config:"PRECISE,prec=200"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "BIGFLOATS",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("BIGFLOATS_RATE", "0.0125")
+	t.Setenv("BIGFLOATS_PRECISE", "3.14159265358979323846264338327950288")
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Rate.Text('g', 10) != "0.0125" || n.Precise.Prec() != 200 {
+		t.Fatalf("unexpected values %v and %v with precision %d", n.Rate, n.Precise, n.Precise.Prec())
+	}
+	if n.Precise.Text('g', 36) != "3.14159265358979323846264338327950288" {
+		t.Fatalf("expected the value with 36 digits, received: %s", n.Precise.Text('g', 36))
+	}
+
+	type InvalidPrec struct {
+		Rate *big.Float `config:"RATE,prec=high"`
+	}
+	err = sc.ParseTo(&InvalidPrec{})
+	if err == nil || !strings.Contains(err.Error(), "prec") {
+		t.Fatalf("expected an invalid prec error, received: %v", err)
+	}
+}
@@ -0,0 +1,351 @@
+// Command configgen generates typed accessor methods for a struct tagged with `config` tags, backed by the getters
+// of a config.ServiceConfig. It is meant to be run with go generate, from a comment next to the struct:
+//
+//	//go:generate go run github.com/potatobeansco/go-config/cmd/configgen -type AppConfig
+//
+// For a struct AppConfig, configgen writes appconfig_accessor.go, declaring an AppConfigAccessor type created with
+// NewAppConfigAccessor, with one method per field. For example, a field Port int tagged `config:"PORT,default=8080"`
+// gets the method GetPort() (int, error), returning the configured PORT, or 8080 when it is not configured.
+//
+// A field tagged with the `required` option returns config.ErrConfigNotFound when its config is not configured.
+// Other fields return their `default` option, or the zero value. Defaults of slice fields are split with the
+// separator given by the -sep flag, since the ArraySeparator is only known at run time.
+//
+// A prefix declared on the struct with an embedded config.Config marker, e.g. config.Config `config:"prefix=MYAPP"`,
+// is used by the accessor when the config.ServiceConfig it is created with has no Prefix, as ParseTo does.
+//
+// Supported field types are string, int, bool, float32, float64, time.Duration, []string and []int. Options other
+// than `default`, `required` and `secure` are not supported, since they change how ParseTo parses a field. Defaults
+// referencing other configs, such as `default=http://{HOST}`, are not supported either, since they are only resolved
+// by ParseTo.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func main() {
+	typeName := flag.String("type", "", "name of the struct to generate accessors for; required")
+	output := flag.String("output", "", "output file name; default <type>_accessor.go in lower case")
+	sep := flag.String("sep", " ", "separator of the elements of slice defaults")
+	flag.Parse()
+
+	if *typeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	err := run(".", *typeName, *output, *sep)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "configgen:", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the accessors of the struct typeName declared in the package in dir.
+func run(dir, typeName, output, sep string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			return err
+		}
+
+		st := findStruct(file, typeName)
+		if st == nil {
+			continue
+		}
+
+		src, err := generate(file.Name.Name, typeName, st, sep)
+		if err != nil {
+			return err
+		}
+
+		if output == "" {
+			output = strings.ToLower(typeName) + "_accessor.go"
+		}
+		return os.WriteFile(filepath.Join(dir, output), src, 0644)
+	}
+
+	return fmt.Errorf("struct %s not found", typeName)
+}
+
+// findStruct returns the struct type named typeName declared in file, or nil.
+func findStruct(file *ast.File, typeName string) *ast.StructType {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if ts.Name.Name != typeName {
+				continue
+			}
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				return st
+			}
+		}
+	}
+
+	return nil
+}
+
+// getter describes how a field type is read: the name of the getter without its WithDefault suffix, and how to
+// write a default value as a Go expression.
+type getter struct {
+	name    string
+	literal func(value, sep string) (string, error)
+}
+
+var getters = map[string]getter{
+	"string": {"GetString", func(v, _ string) (string, error) {
+		return strconv.Quote(v), nil
+	}},
+	"int": {"GetInt", func(v, _ string) (string, error) {
+		return intLiteral(v)
+	}},
+	"bool": {"GetBool", func(v, _ string) (string, error) {
+		b, err := strconv.ParseBool(v)
+		return strconv.FormatBool(b), err
+	}},
+	"float32": {"GetFloat32", func(v, _ string) (string, error) {
+		return floatLiteral(v, 32)
+	}},
+	"float64": {"GetFloat64", func(v, _ string) (string, error) {
+		return floatLiteral(v, 64)
+	}},
+	"time.Duration": {"GetDuration", func(v, _ string) (string, error) {
+		d, err := time.ParseDuration(v)
+		return fmt.Sprintf("time.Duration(%d)", d), err
+	}},
+	"[]string": {"GetStringArray", func(v, sep string) (string, error) {
+		elems := make([]string, 0)
+		for _, e := range strings.Split(v, sep) {
+			elems = append(elems, strconv.Quote(e))
+		}
+		return "[]string{" + strings.Join(elems, ", ") + "}", nil
+	}},
+	"[]int": {"GetIntArray", func(v, sep string) (string, error) {
+		elems := make([]string, 0)
+		for _, e := range strings.Split(v, sep) {
+			literal, err := intLiteral(e)
+			if err != nil {
+				return "", err
+			}
+			elems = append(elems, literal)
+		}
+		return "[]int{" + strings.Join(elems, ", ") + "}", nil
+	}},
+}
+
+// intLiteral returns the Go literal of the integer v. The literal is formatted again rather than copied, since
+// strconv accepts values that are not valid, or have another meaning, in Go source, e.g. "08".
+func intLiteral(v string) (string, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(n), nil
+}
+
+// floatLiteral returns the Go literal of the floating-point number v of bitSize bits, formatted again like in
+// intLiteral. Infinities and NaN, which strconv accepts as e.g. "inf" and "NaN", have no literal and are rejected.
+func floatLiteral(v string, bitSize int) (string, error) {
+	f, err := strconv.ParseFloat(v, bitSize)
+	if err != nil {
+		return "", err
+	}
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return "", errors.New("infinities and NaN are not supported")
+	}
+
+	return strconv.FormatFloat(f, 'g', -1, bitSize), nil
+}
+
+var zeroValues = map[string]string{
+	"string":        `""`,
+	"int":           "0",
+	"bool":          "false",
+	"float32":       "0",
+	"float64":       "0",
+	"time.Duration": "0",
+	"[]string":      "nil",
+	"[]int":         "nil",
+}
+
+// generate returns the formatted source of the accessors of the struct st named typeName, in package pkg.
+func generate(pkg, typeName string, st *ast.StructType, sep string) ([]byte, error) {
+	accessor := typeName + "Accessor"
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by configgen; DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	fmt.Fprintf(&b, "import (\n\t\"time\"\n\n\tconfig \"github.com/potatobeansco/go-config\"\n)\n\n")
+	fmt.Fprintf(&b, "// %s reads the configs of %s one by one through a config.ServiceConfig.\n", accessor, typeName)
+	fmt.Fprintf(&b, "type %s struct {\n\tsc config.ServiceConfig\n}\n\n", accessor)
+	if prefix, ok := structPrefix(st); ok {
+		fmt.Fprintf(&b, "// New%s returns an %s reading configs through sc, with the prefix %s declared on %s\n", accessor, accessor, prefix, typeName)
+		fmt.Fprintf(&b, "// when sc has no Prefix.\n")
+		fmt.Fprintf(&b, "func New%s(sc config.ServiceConfig) %s {\n", accessor, accessor)
+		fmt.Fprintf(&b, "\tif sc.Prefix == \"\" {\n\t\tsc.Prefix = %q\n\t}\n\treturn %s{sc: sc}\n}\n", prefix, accessor)
+	} else {
+		fmt.Fprintf(&b, "// New%s returns an %s reading configs through sc.\n", accessor, accessor)
+		fmt.Fprintf(&b, "func New%s(sc config.ServiceConfig) %s {\n\treturn %s{sc: sc}\n}\n", accessor, accessor, accessor)
+	}
+
+	usesTime := false
+	for _, field := range st.Fields.List {
+		if field.Tag == nil || len(field.Names) == 0 {
+			continue
+		}
+
+		tagValue, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			return nil, err
+		}
+		tag, ok := reflect.StructTag(tagValue).Lookup("config")
+		if !ok {
+			continue
+		}
+
+		fieldType := types(field.Type)
+		g, ok := getters[fieldType]
+		if !ok {
+			return nil, fmt.Errorf("field %s: unsupported type %s", field.Names[0].Name, fieldType)
+		}
+		usesTime = usesTime || fieldType == "time.Duration"
+
+		name, opts, err := parseTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Names[0].Name, err)
+		}
+
+		for _, f := range field.Names {
+			fmt.Fprintf(&b, "\n// Get%s returns the config %s", f.Name, name)
+			switch def, hasDefault := opts["default"]; {
+			case hasDefault:
+				if strings.ContainsAny(def, "{}") {
+					return nil, fmt.Errorf("field %s: default %q references other configs, which is not supported", f.Name, def)
+				}
+				literal, err := g.literal(def, sep)
+				if err != nil {
+					return nil, fmt.Errorf("field %s: invalid default %q: %w", f.Name, def, err)
+				}
+				fmt.Fprintf(&b, ", or %s when it is not configured.\n", def)
+				fmt.Fprintf(&b, "func (a %s) Get%s() (%s, error) {\n", accessor, f.Name, fieldType)
+				fmt.Fprintf(&b, "\treturn a.sc.%sWithDefault(%q, %s)\n}\n", g.name, name, literal)
+			case hasKey(opts, "required"):
+				fmt.Fprintf(&b, ", which is required: config.ErrConfigNotFound is returned when it is not configured.\n")
+				fmt.Fprintf(&b, "func (a %s) Get%s() (%s, error) {\n", accessor, f.Name, fieldType)
+				fmt.Fprintf(&b, "\treturn a.sc.%s(%q)\n}\n", g.name, name)
+			default:
+				fmt.Fprintf(&b, ", or the zero value when it is not configured.\n")
+				fmt.Fprintf(&b, "func (a %s) Get%s() (%s, error) {\n", accessor, f.Name, fieldType)
+				fmt.Fprintf(&b, "\treturn a.sc.%sWithDefault(%q, %s)\n}\n", g.name, name, zeroValues[fieldType])
+			}
+		}
+	}
+
+	src := b.Bytes()
+	if !usesTime {
+		src = bytes.Replace(src, []byte("\t\"time\"\n\n"), nil, 1)
+	}
+
+	return format.Source(src)
+}
+
+// structPrefix returns the prefix declared on st with an embedded config.Config marker, e.g.
+// config.Config `config:"prefix=MYAPP"`, like ParseTo reads it.
+func structPrefix(st *ast.StructType) (string, bool) {
+	for _, field := range st.Fields.List {
+		sel, ok := field.Type.(*ast.SelectorExpr)
+		if len(field.Names) != 0 || !ok || sel.Sel.Name != "Config" || field.Tag == nil {
+			continue
+		}
+
+		tagValue, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		for _, setting := range strings.Split(reflect.StructTag(tagValue).Get("config"), ",") {
+			key, value, _ := strings.Cut(setting, "=")
+			if strings.TrimSpace(key) == "prefix" {
+				return value, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// types returns the Go source of the type expression expr, e.g. "[]string" or "time.Duration".
+func types(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return types(t.X) + "." + t.Sel.Name
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "[]" + types(t.Elt)
+		}
+	case *ast.StarExpr:
+		return "*" + types(t.X)
+	}
+
+	return fmt.Sprintf("%T", expr)
+}
+
+// parseTag splits a `config` tag into the config name and its options, rejecting the options that configgen does not
+// support.
+func parseTag(tag string) (string, map[string]string, error) {
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" || name == "-" {
+		return "", nil, errors.New("computed fields and fields without a config name are not supported")
+	}
+
+	opts := make(map[string]string)
+	for _, part := range parts[1:] {
+		key, value, _ := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+		switch key {
+		case "default", "required", "secure":
+			opts[key] = value
+		default:
+			return "", nil, fmt.Errorf("option %s is not supported", key)
+		}
+	}
+
+	return name, opts, nil
+}
+
+func hasKey(m map[string]string, key string) bool {
+	_, ok := m[key]
+	return ok
+}
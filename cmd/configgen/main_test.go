@@ -0,0 +1,181 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(`package app
+
+import "time"
+
+type AppConfig struct {
+	Port     int           `+"`config:\"PORT,default=8080\"`"+`
+	Host     string        `+"`config:\"HOST,required\"`"+`
+	Hosts    []string      `+"`config:\"HOSTS,default=a b\"`"+`
+	Timeout  time.Duration `+"`config:\"TIMEOUT,default=1m30s\"`"+`
+	Password string        `+"`config:\"PASSWORD,secure\"`"+`
+	Internal string
+}
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = run(dir, "AppConfig", "", " ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, "appconfig_accessor.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"// Code generated by configgen; DO NOT EDIT.",
+		"func NewAppConfigAccessor(sc config.ServiceConfig) AppConfigAccessor {",
+		"func (a AppConfigAccessor) GetPort() (int, error) {\n\treturn a.sc.GetIntWithDefault(\"PORT\", 8080)\n}",
+		"func (a AppConfigAccessor) GetHost() (string, error) {\n\treturn a.sc.GetString(\"HOST\")\n}",
+		"return a.sc.GetStringArrayWithDefault(\"HOSTS\", []string{\"a\", \"b\"})",
+		"return a.sc.GetDurationWithDefault(\"TIMEOUT\", time.Duration(90000000000))",
+		"return a.sc.GetStringWithDefault(\"PASSWORD\", \"\")",
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Fatalf("expected generated code to contain %q, received:\n%s", expected, src)
+		}
+	}
+	if strings.Contains(string(src), "Internal") {
+		t.Fatalf("expected untagged fields to be skipped, received:\n%s", src)
+	}
+}
+
+func TestRun_unsupported(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(`package app
+
+type AppConfig struct {
+	Key []byte `+"`config:\"KEY,hex\"`"+`
+}
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = run(dir, "AppConfig", "", " ")
+	if err == nil || !strings.Contains(err.Error(), "Key") {
+		t.Fatalf("expected error naming the field, received: %v", err)
+	}
+
+	err = run(dir, "Missing", "", " ")
+	if err == nil {
+		t.Fatal("expected error for a missing struct")
+	}
+}
+
+func TestRun_numberDefaults(t *testing.T) {
+	tests := []struct {
+		fieldType string
+		def       string
+		expected  string
+	}{
+		{"int", "08", "GetIntWithDefault(\"VALUE\", 8)"},
+		{"int", "+5", "GetIntWithDefault(\"VALUE\", 5)"},
+		{"float64", "1e3", "GetFloat64WithDefault(\"VALUE\", 1000)"},
+		{"float64", "0x1p-2", "GetFloat64WithDefault(\"VALUE\", 0.25)"},
+		{"float32", "0.1", "GetFloat32WithDefault(\"VALUE\", 0.1)"},
+		{"[]int", "08 -1", "GetIntArrayWithDefault(\"VALUE\", []int{8, -1})"},
+		{"float64", "inf", ""},
+		{"float64", "-Infinity", ""},
+		{"float32", "NaN", ""},
+		{"float32", "1e39", ""},
+		{"int", "1_000", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fieldType+"="+test.def, func(t *testing.T) {
+			dir := t.TempDir()
+			err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(`package app
+
+type AppConfig struct {
+	Value `+test.fieldType+" `config:\"VALUE,default="+test.def+"\"`"+`
+}
+`), 0600)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = run(dir, "AppConfig", "", " ")
+			if test.expected == "" {
+				if err == nil || !strings.Contains(err.Error(), "invalid default") {
+					t.Fatalf("expected invalid default error, received: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			src, err := os.ReadFile(filepath.Join(dir, "appconfig_accessor.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(src), test.expected) {
+				t.Fatalf("expected generated code to contain %q, received:\n%s", test.expected, src)
+			}
+		})
+	}
+}
+
+func TestRun_prefix(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(`package app
+
+import config "github.com/potatobeansco/go-config"
+
+type AppConfig struct {
+	config.Config `+"`config:\"prefix=MYAPP\"`"+`
+	Port int `+"`config:\"PORT,default=8080\"`"+`
+}
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = run(dir, "AppConfig", "", " ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, "appconfig_accessor.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "func NewAppConfigAccessor(sc config.ServiceConfig) AppConfigAccessor {\n\tif sc.Prefix == \"\" {\n\t\tsc.Prefix = \"MYAPP\"\n\t}\n\treturn AppConfigAccessor{sc: sc}\n}"
+	if !strings.Contains(string(src), expected) {
+		t.Fatalf("expected generated code to contain %q, received:\n%s", expected, src)
+	}
+}
+
+func TestRun_interpolatedDefault(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(`package app
+
+type AppConfig struct {
+	URL string `+"`config:\"URL,default=http://{HOST}\"`"+`
+}
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = run(dir, "AppConfig", "", " ")
+	if err == nil || !strings.Contains(err.Error(), "URL") || !strings.Contains(err.Error(), "references other configs") {
+		t.Fatalf("expected error naming the field, received: %v", err)
+	}
+}
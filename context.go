@@ -0,0 +1,156 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type contextDefaultsKey struct{}
+
+// ParseToCtx is like ParseTo, but every lookup in Sources is bounded by ctx, so that a slow remote source cannot hang
+// startup. Sources implementing ContextSource are given ctx, and parsing stops with an error wrapping ctx.Err() as
+// soon as ctx is done. The environment is read without regard to ctx, so ctx has no effect when Sources is nil.
+//
+// A field tagged with the `timeout` option, e.g. `config:"DB_PASSWORD,timeout=2s"`, is given its own deadline within
+// ctx, so that one slow key fails on its own rather than consuming the time of the whole parse. ParseTo honors the
+// option too, bounding the lookups of the field by the timeout alone.
+func (sc ServiceConfig) ParseToCtx(ctx context.Context, obj interface{}) error {
+	assertPointer(obj)
+	sc.ctx = ctx
+	return sc.parse(obj, &parseState{})
+}
+
+// resolveField resolves the value of f, from chunks when it is tagged with the `chunked` option. Lookups in Sources
+// are bounded by the `timeout` option of the field, e.g. `config:"DB_PASSWORD,timeout=2s"`, in addition to the
+// context given to ParseToCtx, if any. As with ParseToCtx, only sources implementing ContextSource can be interrupted.
+func (sc ServiceConfig) resolveField(f configField) (string, string, bool, error) {
+	limit, ok := f.opts.get("timeout")
+	if !ok {
+		return sc.resolveFieldValue(f)
+	}
+
+	timeout, err := time.ParseDuration(limit)
+	if err != nil || timeout <= 0 {
+		return "", "", false, fmt.Errorf("invalid timeout `%s`, expected a positive duration", limit)
+	}
+
+	parent := sc.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	sc.ctx = ctx
+	configData, origin, exist, err := sc.resolveFieldValue(f)
+	if err != nil && errors.Is(err, context.DeadlineExceeded) && parent.Err() == nil {
+		return "", "", false, fmt.Errorf("lookup timed out after %s: %w", timeout, err)
+	}
+	return configData, origin, exist, err
+}
+
+func (sc ServiceConfig) resolveFieldValue(f configField) (string, string, bool, error) {
+	if f.opts.has("chunked") {
+		return sc.resolveChunked(f.name)
+	}
+	return sc.resolve(f.name)
+}
+
+// ContextWithDefaults returns a copy of ctx carrying defaults, a map of config names, without the prefix, to values.
+// The WithContext getters fall back to these values when a config is not configured, e.g. to thread per-tenant
+// overrides through a request. Defaults already carried by ctx are kept unless defaults has the same name.
+func ContextWithDefaults(ctx context.Context, defaults map[string]string) context.Context {
+	merged := make(map[string]string)
+	if parent, ok := ctx.Value(contextDefaultsKey{}).(map[string]string); ok {
+		for name, value := range parent {
+			merged[name] = value
+		}
+	}
+	for name, value := range defaults {
+		merged[name] = value
+	}
+
+	return context.WithValue(ctx, contextDefaultsKey{}, merged)
+}
+
+// lookupContext looks name up like lookup, falling back to the defaults carried by ctx.
+func (sc ServiceConfig) lookupContext(ctx context.Context, name string) (string, bool, error) {
+	configData, exist, err := sc.lookup(name)
+	if err != nil || exist {
+		return configData, exist, err
+	}
+
+	defaults, _ := ctx.Value(contextDefaultsKey{}).(map[string]string)
+	configData, exist = defaults[name]
+	return configData, exist, nil
+}
+
+// GetStringWithContext is like GetStringWithDefault, but when the config is not configured, the default carried by
+// ctx for name is used before defaultValue, see ContextWithDefaults. The precedence is therefore: the configured
+// value, then the context default, then defaultValue.
+func (sc ServiceConfig) GetStringWithContext(ctx context.Context, name string, defaultValue string, opts ...GetOption) (string, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookupContext(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		return defaultValue, nil
+	}
+	return configData, nil
+}
+
+// GetIntWithContext is like GetIntWithDefault, with the precedence of GetStringWithContext.
+func (sc ServiceConfig) GetIntWithContext(ctx context.Context, name string, defaultValue int, opts ...GetOption) (int, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookupContext(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return defaultValue, nil
+	}
+	return sc.parseInt(configData)
+}
+
+// GetBoolWithContext is like GetBoolWithDefault, with the precedence of GetStringWithContext.
+func (sc ServiceConfig) GetBoolWithContext(ctx context.Context, name string, defaultValue bool, opts ...GetOption) (bool, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookupContext(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	if !exist {
+		return defaultValue, nil
+	}
+	return sc.parseBool(configData)
+}
+
+// GetFloat64WithContext is like GetFloat64WithDefault, with the precedence of GetStringWithContext.
+func (sc ServiceConfig) GetFloat64WithContext(ctx context.Context, name string, defaultValue float64, opts ...GetOption) (float64, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookupContext(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return defaultValue, nil
+	}
+	return sc.parseFloat(configData, 64)
+}
+
+// GetDurationWithContext is like GetDurationWithDefault, with the precedence of GetStringWithContext.
+func (sc ServiceConfig) GetDurationWithContext(ctx context.Context, name string, defaultValue time.Duration, opts ...GetOption) (time.Duration, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookupContext(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return defaultValue, nil
+	}
+	return time.ParseDuration(configData)
+}
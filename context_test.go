@@ -0,0 +1,155 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServiceConfig_GetWithContext(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "CTX",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("CTX_REGION", "env")
+
+	ctx := ContextWithDefaults(context.Background(), map[string]string{
+		"REGION":  "context",
+		"TENANT":  "acme",
+		"LIMIT":   "10",
+		"TIMEOUT": "5s",
+	})
+	ctx = ContextWithDefaults(ctx, map[string]string{"LIMIT": "20"})
+
+	region, err := sc.GetStringWithContext(ctx, "REGION", "static")
+	if err != nil || region != "env" {
+		t.Fatalf("expected configured value to win, received: %v, %v", region, err)
+	}
+
+	tenant, err := sc.GetStringWithContext(ctx, "TENANT", "static")
+	if err != nil || tenant != "acme" {
+		t.Fatalf("expected context default, received: %v, %v", tenant, err)
+	}
+
+	tenant, err = sc.GetStringWithContext(context.Background(), "TENANT", "static")
+	if err != nil || tenant != "static" {
+		t.Fatalf("expected static default, received: %v, %v", tenant, err)
+	}
+
+	limit, err := sc.GetIntWithContext(ctx, "LIMIT", 1)
+	if err != nil || limit != 20 {
+		t.Fatalf("expected overridden context default, received: %v, %v", limit, err)
+	}
+
+	timeout, err := sc.GetDurationWithContext(ctx, "TIMEOUT", time.Second)
+	if err != nil || timeout != 5*time.Second {
+		t.Fatalf("expected context default, received: %v, %v", timeout, err)
+	}
+
+	_, err = sc.GetBoolWithContext(ctx, "TENANT", false)
+	if err == nil {
+		t.Fatal("expected parse error for context default")
+	}
+}
+
+// blockingSource is a ContextSource that never answers before ctx is done.
+type blockingSource struct{}
+
+func (blockingSource) Lookup(key string) (string, bool, error) {
+	select {}
+}
+
+func (blockingSource) LookupContext(ctx context.Context, key string) (string, bool, error) {
+	<-ctx.Done()
+	return "", false, ctx.Err()
+}
+
+func TestServiceConfig_ParseToCtx(t *testing.T) {
+	type TestConfig struct {
+		Port int `config:"PORT"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "PARSECTX",
+		ArraySeparator: " ",
+		Sources:        []Source{MapSource{"PARSECTX_HOST": "localhost"}, blockingSource{}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := sc.ParseToCtx(ctx, &TestConfig{})
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "PARSECTX_PORT") {
+		t.Fatalf("expected a deadline error naming the key, received: %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	sc.Sources = []Source{MapSource{"PARSECTX_PORT": "8080"}}
+	err = sc.ParseToCtx(cancelled, &TestConfig{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a cancellation error, received: %v", err)
+	}
+
+	t.Setenv("PARSECTX_PORT", "9090")
+	sc.Sources = nil
+	n := &TestConfig{}
+	err = sc.ParseToCtx(cancelled, n)
+	if err != nil || n.Port != 9090 {
+		t.Fatalf("expected the environment to be read regardless of ctx, received: %v, %v", n, err)
+	}
+}
+
+func TestServiceConfig_ParseToCtx_timeout(t *testing.T) {
+	type TestConfig struct {
+		Host     string `config:"HOST,timeout=1s"`
+		Password string `config:"PASSWORD,timeout=10ms"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "FIELDTIMEOUT",
+		ArraySeparator: " ",
+		Sources:        []Source{MapSource{"FIELDTIMEOUT_HOST": "localhost"}, blockingSource{}},
+	}
+
+	start := time.Now()
+	err := sc.ParseToCtx(context.Background(), &TestConfig{})
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "FIELDTIMEOUT_PASSWORD") || !strings.Contains(err.Error(), "timed out after 10ms") {
+		t.Fatalf("expected a timeout error naming the key, received: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the field timeout to end the lookup early, took %s", elapsed)
+	}
+
+	err = sc.ParseTo(&TestConfig{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected ParseTo to honor the field timeout, received: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	type LongConfig struct {
+		Password string `config:"PASSWORD,timeout=1h"`
+	}
+	err = sc.ParseToCtx(ctx, &LongConfig{})
+	if !errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "timed out after") {
+		t.Fatalf("expected the overall deadline to apply, received: %v", err)
+	}
+
+	sc.Sources = []Source{MapSource{"FIELDTIMEOUT_HOST": "localhost", "FIELDTIMEOUT_PASSWORD": "s3cret"}}
+	n := &TestConfig{}
+	err = sc.ParseToCtx(context.Background(), n)
+	if err != nil || n.Host != "localhost" || n.Password != "s3cret" {
+		t.Fatalf("expected values within the timeout to be read, received: %v, %v", n, err)
+	}
+
+	type InvalidConfig struct {
+		Host string `config:"HOST,timeout=soon"`
+	}
+	err = sc.ParseToCtx(context.Background(), &InvalidConfig{})
+	if err == nil || !strings.Contains(err.Error(), "invalid timeout") {
+		t.Fatalf("expected error for an invalid timeout, received: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// GetStringCSV parses the config value as a single CSV record, as defined by RFC 4180, and returns its fields.
+// Unlike GetStringArray, fields may be quoted to contain commas, quotes or the ArraySeparator, e.g.
+// `a,"b, c","say ""hi"""` is parsed into "a", "b, c" and `say "hi"`. ParseTo applies it to []string fields tagged
+// with the `csv` option.
+func (sc ServiceConfig) GetStringCSV(name string, opts ...GetOption) ([]string, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+
+	record, err := parseCSV(configData)
+	if err != nil {
+		return nil, sc.reformatParseError(name, err)
+	}
+	return record, nil
+}
+
+// parseCSV parses configData as a single CSV record.
+func parseCSV(configData string) ([]string, error) {
+	r := csv.NewReader(strings.NewReader(configData))
+	record, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.Read()
+	if !errors.Is(err, io.EOF) {
+		return nil, errors.New("expected a single CSV record")
+	}
+
+	return record, nil
+}
+
+// formatCSV writes record as a single CSV record, the inverse of parseCSV.
+func formatCSV(record []string) string {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	_ = w.Write(record)
+	w.Flush()
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServiceConfig_GetStringCSV(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "CSV",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("CSV_COLUMNS", `a,"b, c","say ""hi"""`)
+	t.Setenv("CSV_BROKEN", `a,"b`)
+	t.Setenv("CSV_LINES", "a,b\nc,d")
+
+	expected := []string{"a", "b, c", `say "hi"`}
+	v, err := sc.GetStringCSV("COLUMNS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Fatalf("unexpected record, received: %q, expected: %q", v, expected)
+	}
+
+	for _, name := range []string{"BROKEN", "LINES"} {
+		_, err = sc.GetStringCSV(name)
+		if err == nil || !strings.Contains(err.Error(), "CSV_"+name) {
+			t.Fatalf("expected error naming the key, received: %v", err)
+		}
+	}
+
+	type TestConfig struct {
+		Columns []string `config:"COLUMNS,csv"`
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(n.Columns, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %q, expected: %q", n.Columns, expected)
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+var (
+	defaultFuncsMu sync.RWMutex
+	defaultFuncs   = map[string]func() (string, error){
+		"hostname": os.Hostname,
+	}
+)
+
+// RegisterDefaultFunc registers fn under name, so that ParseTo can call it to produce the value of a field tagged
+// with `default_func=name` when its config does not exist, e.g. `config:"NODE,default_func=hostname"`. Registering an
+// existing name replaces its function.
+//
+// The name hostname is registered with os.Hostname.
+func RegisterDefaultFunc(name string, fn func() (string, error)) {
+	defaultFuncsMu.Lock()
+	defer defaultFuncsMu.Unlock()
+
+	defaultFuncs[name] = fn
+}
+
+// callDefaultFunc returns the value produced by the default function registered under name.
+func callDefaultFunc(name string) (string, error) {
+	defaultFuncsMu.RLock()
+	fn, ok := defaultFuncs[name]
+	defaultFuncsMu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("unknown default function %q, register it with RegisterDefaultFunc", name)
+	}
+
+	value, err := fn()
+	if err != nil {
+		return "", fmt.Errorf("default function %s: %w", name, err)
+	}
+	return value, nil
+}
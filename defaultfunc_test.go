@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestServiceConfig_ParseTo_defaultFunc(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "DEFAULTFUNC",
+		ArraySeparator: " ",
+		RequireAll:     true,
+	}
+
+	RegisterDefaultFunc("region", func() (string, error) {
+		return "eu-west-1", nil
+	})
+
+	t.Setenv("DEFAULTFUNC_ZONE", "b")
+
+	type TestConfig struct {
+		Node   string `config:"NODE,default_func=hostname"`
+		Region string `config:"REGION,default_func=region,default=us-east-1"`
+		Zone   string `config:"ZONE,default_func=region"`
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{Node: hostname, Region: "eu-west-1", Zone: "b"}
+	if *n != *expected {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+
+	RegisterDefaultFunc("broken", func() (string, error) {
+		return "", errors.New("unavailable")
+	})
+
+	type BrokenConfig struct {
+		Node string `config:"NODE,default_func=broken"`
+	}
+
+	err = sc.ParseTo(&BrokenConfig{})
+	if err == nil || !strings.Contains(err.Error(), "DEFAULTFUNC_NODE") || !strings.Contains(err.Error(), "unavailable") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+
+	type UnknownConfig struct {
+		Node string `config:"NODE,default_func=nope"`
+	}
+
+	err = sc.ParseTo(&UnknownConfig{})
+	if err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Fatalf("expected unknown function error, received: %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"strings"
+)
+
+// FieldDescriptor describes a config field of a struct and the options of its `config` tag, as returned by Describe.
+type FieldDescriptor struct {
+	// The name of the struct field.
+	Field string
+	// The full name of the config, including the prefix, or empty for computed fields.
+	Key string
+	// The Go type of the field, e.g. "time.Duration".
+	Type string
+	// The kind of the field, e.g. "int64" for a time.Duration.
+	Kind string
+	// Whether the config must be configured, see the `required` option and RequireAll.
+	Required bool
+	// The default for the Environment, or the `default` option, and whether the field has one.
+	Default    string
+	HasDefault bool
+	// The `compute` option, for fields computed from other configs.
+	Compute string
+	// The values allowed by the `oneof` option.
+	OneOf []string
+	// The `pattern` option.
+	Pattern string
+	// The `min` and `max` options, as written in the tag.
+	Min string
+	Max string
+	// The `len`, `minlen` and `maxlen` options of slice fields, as written in the tag.
+	Len    string
+	MinLen string
+	MaxLen string
+	// Whether the field is tagged with the `secure` option.
+	Secure bool
+}
+
+// Describe returns a descriptor for every config field of the struct pointed by obj, in field declaration order,
+// e.g. to build configuration UIs or documentation. The environment is never read.
+//
+// Like ParseTo, Describe only considers the tagged fields of the struct itself: a nested struct is described as a
+// single field of its type.
+func (sc ServiceConfig) Describe(obj interface{}) []FieldDescriptor {
+	assertPointer(obj)
+	sc = sc.withStructSettings(obj)
+
+	descriptors := make([]FieldDescriptor, 0)
+	for _, f := range configFields(obj) {
+		if f.name == "" {
+			continue
+		}
+
+		d := FieldDescriptor{
+			Field:    f.field.Name,
+			Type:     f.field.Type.String(),
+			Kind:     f.field.Type.Kind().String(),
+			Required: sc.isRequired(f),
+			Secure:   f.opts.has("secure"),
+		}
+		if f.name != "-" {
+			d.Key = sc.getConfigName(f.name)
+		}
+		d.Default, d.HasDefault = sc.defaultOption(f)
+		d.Compute, _ = f.opts.get("compute")
+		if oneof, ok := f.opts.get("oneof"); ok {
+			d.OneOf = strings.Split(oneof, "|")
+		}
+		d.Pattern, _ = f.opts.get("pattern")
+		d.Min, _ = f.opts.get("min")
+		d.Max, _ = f.opts.get("max")
+		d.Len, _ = f.opts.get("len")
+		d.MinLen, _ = f.opts.get("minlen")
+		d.MaxLen, _ = f.opts.get("maxlen")
+
+		descriptors = append(descriptors, d)
+	}
+
+	return descriptors
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestServiceConfig_Describe(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "DESCRIBE",
+		ArraySeparator: " ",
+	}
+
+	type TestConfig struct {
+		Mode    string        `config:"MODE,oneof=dev|prod,default=dev"`
+		Timeout time.Duration `config:"TIMEOUT,required,min=1s,max=1m"`
+		Token   string        `config:"TOKEN,secure,pattern=[0-9a-f]+"`
+		URL     string        `config:"-,compute=http://{MODE}"`
+		Ignored string
+	}
+
+	t.Setenv("DESCRIBE_MODE", "not read")
+
+	descriptors := sc.Describe(&TestConfig{})
+	expected := []FieldDescriptor{
+		{Field: "Mode", Key: "DESCRIBE_MODE", Type: "string", Kind: "string", Default: "dev", HasDefault: true, OneOf: []string{"dev", "prod"}},
+		{Field: "Timeout", Key: "DESCRIBE_TIMEOUT", Type: "time.Duration", Kind: "int64", Required: true, Min: "1s", Max: "1m"},
+		{Field: "Token", Key: "DESCRIBE_TOKEN", Type: "string", Kind: "string", Pattern: "[0-9a-f]+", Secure: true},
+		{Field: "URL", Type: "string", Kind: "string", Compute: "http://{MODE}"},
+	}
+
+	if !reflect.DeepEqual(descriptors, expected) {
+		t.Fatalf("descriptors are not the same with expectation, received: %+v, expected: %+v", descriptors, expected)
+	}
+}
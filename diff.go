@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldDiff describes a config field whose value differs between two structs, see DiffStructs.
+type FieldDiff struct {
+	// The config name of the field, or the struct field name for computed fields.
+	Key string
+	// The values in the first and second struct, formatted with %v, or "********" for fields tagged with the
+	// `secure` option.
+	Old string
+	New string
+}
+
+// DiffStructs compares the config fields of the structs pointed by a and b, which must be of the same type, and
+// returns the fields whose values differ, in field declaration order, e.g. to review a proposed configuration
+// against the running one. Secure fields are reported when they differ, but their values are masked. Neither struct
+// is modified.
+func DiffStructs(a, b interface{}) []FieldDiff {
+	assertPointer(a)
+	assertPointer(b)
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		panic(fmt.Sprintf("cannot compare different types %T and %T", a, b))
+	}
+
+	fieldsA, fieldsB := configFields(a), configFields(b)
+	diffs := make([]FieldDiff, 0)
+	for i, fa := range fieldsA {
+		va, vb := fa.value.Interface(), fieldsB[i].value.Interface()
+		if reflect.DeepEqual(va, vb) {
+			continue
+		}
+
+		d := FieldDiff{Key: fa.key(), Old: "********", New: "********"}
+		if !fa.opts.has("secure") {
+			d.Old, d.New = fmt.Sprintf("%v", va), fmt.Sprintf("%v", vb)
+		}
+		diffs = append(diffs, d)
+	}
+
+	return diffs
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffStructs(t *testing.T) {
+	type TestConfig struct {
+		Port     int      `config:"PORT"`
+		Hosts    []string `config:"HOSTS"`
+		Password string   `config:"PASSWORD,secure"`
+		Token    string   `config:"TOKEN,secure"`
+		URL      string   `config:"-,compute=http://{PORT}"`
+		Internal string
+	}
+
+	running := &TestConfig{Port: 8080, Hosts: []string{"a"}, Password: "old", Token: "same", URL: "http://8080", Internal: "x"}
+	proposed := &TestConfig{Port: 9090, Hosts: []string{"a"}, Password: "new", Token: "same", URL: "http://9090", Internal: "y"}
+
+	diffs := DiffStructs(running, proposed)
+	expected := []FieldDiff{
+		{Key: "PORT", Old: "8080", New: "9090"},
+		{Key: "PASSWORD", Old: "********", New: "********"},
+		{Key: "URL", Old: "http://8080", New: "http://9090"},
+	}
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Fatalf("differences are not the same with expectation, received: %v, expected: %v", diffs, expected)
+	}
+
+	if len(DiffStructs(running, running)) != 0 {
+		t.Fatal("expected no differences for the same struct")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for different types")
+		}
+	}()
+	DiffStructs(running, &struct{}{})
+}
@@ -5,5 +5,6 @@ package config
 import "errors"
 
 var (
-	ErrConfigNotFound = errors.New("no configuration match with key")
+	ErrConfigNotFound   = errors.New("no configuration match with key")
+	ErrNoArraySeparator = errors.New("ArraySeparator is empty, set it to the token that separates array elements, e.g. \" \" or \",\"")
 )
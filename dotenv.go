@@ -0,0 +1,176 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrReadOnly is returned by methods that modify the environment when ServiceConfig.ReadOnly is set.
+var ErrReadOnly = errors.New("config is read-only, the environment cannot be modified")
+
+// LoadEnvFile reads the dotenv file at path and sets every variable in it into the environment with os.Setenv.
+// Variables that already exist are only replaced when overwrite is true. Names in the file are used as they are,
+// without adding the Prefix.
+//
+// Every line is either empty, a comment starting with "#", or an assignment of the form NAME=VALUE, optionally
+// preceded by "export ". Values may be quoted with single quotes, taken literally, or with double quotes, in which
+// \n, \t, \" and \\ are unescaped. Unquoted values end at a " #" comment and are trimmed.
+func (sc ServiceConfig) LoadEnvFile(path string, overwrite bool) error {
+	if sc.ReadOnly {
+		return ErrReadOnly
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return loadEnv(f, path, overwrite)
+}
+
+// LoadEnvFS is like LoadEnvFile, but reads the dotenv file at path from fsys, e.g. an embed.FS holding the defaults
+// of a self-contained binary. A file that does not exist is an error wrapping fs.ErrNotExist.
+func (sc ServiceConfig) LoadEnvFS(fsys fs.FS, path string, overwrite bool) error {
+	if sc.ReadOnly {
+		return ErrReadOnly
+	}
+
+	f, err := fsys.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return loadEnv(f, path, overwrite)
+}
+
+// LoadEnvCascade loads the dotenv files of the current directory in the conventional order of precedence: ".env",
+// then ".env.local", then ".env.<environment>", each one overwriting the variables set by the previous ones, and the
+// existing environment. Files that do not exist are skipped. When environment is empty, only the first two files
+// are loaded.
+func (sc ServiceConfig) LoadEnvCascade(environment string) error {
+	if sc.ReadOnly {
+		return ErrReadOnly
+	}
+
+	paths := []string{".env", ".env.local"}
+	if environment != "" {
+		paths = append(paths, ".env."+environment)
+	}
+
+	for _, path := range paths {
+		err := sc.LoadEnvFile(path, true)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetDefault sets the environment variable of the config name to value, unless it already exists, as told by
+// LookupFunc when it is set.
+func (sc ServiceConfig) SetDefault(name string, value string) error {
+	if sc.ReadOnly {
+		return ErrReadOnly
+	}
+
+	_, err := sc.expandPrefix()
+	if err != nil {
+		return err
+	}
+
+	key := sc.getConfigName(name)
+	if _, exist := sc.lookupEnv(key); exist {
+		return nil
+	}
+
+	return os.Setenv(key, value)
+}
+
+// loadEnv parses the dotenv content of r and sets its variables into the environment. path is used in errors.
+func loadEnv(r io.Reader, path string, overwrite bool) error {
+	vars, err := parseDotenv(r)
+	if err != nil {
+		return fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+
+	for _, v := range vars {
+		if _, exist := os.LookupEnv(v[0]); exist && !overwrite {
+			continue
+		}
+
+		err = os.Setenv(v[0], v[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseDotenv parses the dotenv content of r into name-value pairs, in the order they appear.
+func parseDotenv(r io.Reader) ([][2]string, error) {
+	vars := make([][2]string, 0)
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("line %d: expected NAME=VALUE", lineNumber)
+		}
+
+		value, err := parseDotenvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		vars = append(vars, [2]string{name, value})
+	}
+
+	return vars, scanner.Err()
+}
+
+func parseDotenvValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	switch value[0] {
+	case '\'':
+		end := strings.IndexByte(value[1:], '\'')
+		if end < 0 {
+			return "", errors.New("unterminated single quote")
+		}
+		return value[1 : end+1], nil
+	case '"':
+		for i := 1; i < len(value); i++ {
+			if value[i] == '\\' {
+				i++
+				continue
+			}
+			if value[i] == '"' {
+				return strconv.Unquote(value[:i+1])
+			}
+		}
+		return "", errors.New("unterminated double quote")
+	default:
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = value[:i]
+		}
+		return strings.TrimSpace(value), nil
+	}
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestServiceConfig_LoadEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	err := os.WriteFile(path, []byte(`
+# comment
+DOTENV_PLAIN=plain value # trailing comment
+export DOTENV_SINGLE='single # kept'
+DOTENV_DOUBLE="line\nbreak \"quoted\""
+DOTENV_EXISTING=new
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("DOTENV_EXISTING", "old")
+	for _, name := range []string{"DOTENV_PLAIN", "DOTENV_SINGLE", "DOTENV_DOUBLE"} {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+
+	sc := ServiceConfig{Prefix: "DOTENV", ArraySeparator: " "}
+	err = sc.LoadEnvFile(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]string{
+		"DOTENV_PLAIN":    "plain value",
+		"DOTENV_SINGLE":   "single # kept",
+		"DOTENV_DOUBLE":   "line\nbreak \"quoted\"",
+		"DOTENV_EXISTING": "old",
+	}
+	for name, value := range expect {
+		if os.Getenv(name) != value {
+			t.Fatalf("unexpected value of %s: %q, expected: %q", name, os.Getenv(name), value)
+		}
+	}
+}
+
+func TestServiceConfig_ReadOnly(t *testing.T) {
+	t.Setenv("READONLY_PORT", "")
+	os.Unsetenv("READONLY_PORT")
+
+	sc := ServiceConfig{Prefix: "READONLY", ArraySeparator: " ", ReadOnly: true}
+
+	err := sc.SetDefault("PORT", "80")
+	if !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, received: %v", err)
+	}
+	if _, exist := os.LookupEnv("READONLY_PORT"); exist {
+		t.Fatal("expected environment to be unmodified")
+	}
+
+	err = sc.LoadEnvFile(filepath.Join(t.TempDir(), ".env"), true)
+	if !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, received: %v", err)
+	}
+
+	sc.ReadOnly = false
+	err = sc.SetDefault("PORT", "80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if os.Getenv("READONLY_PORT") != "80" {
+		t.Fatalf("expected default to be set, received: %q", os.Getenv("READONLY_PORT"))
+	}
+}
+
+func TestServiceConfig_LoadEnvCascade(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		".env":            "CASCADE_A=env\nCASCADE_B=env\nCASCADE_C=env\n",
+		".env.local":      "CASCADE_B=local\nCASCADE_C=local\n",
+		".env.production": "CASCADE_C=production\n",
+	}
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"CASCADE_A", "CASCADE_B", "CASCADE_C"} {
+		t.Setenv(name, "existing")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+	})
+
+	sc := ServiceConfig{Prefix: "CASCADE", ArraySeparator: " "}
+	err = sc.LoadEnvCascade("production")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]string{"CASCADE_A": "env", "CASCADE_B": "local", "CASCADE_C": "production"}
+	for name, value := range expect {
+		if os.Getenv(name) != value {
+			t.Fatalf("unexpected value of %s: %q, expected: %q", name, os.Getenv(name), value)
+		}
+	}
+
+	err = sc.LoadEnvCascade("staging")
+	if err != nil {
+		t.Fatalf("expected missing files to be skipped, received: %v", err)
+	}
+}
+
+func TestServiceConfig_LoadEnvFS(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "ENVFS",
+		ArraySeparator: " ",
+	}
+
+	fsys := fstest.MapFS{
+		"defaults/.env": &fstest.MapFile{Data: []byte("ENVFS_PORT=8080\nexport ENVFS_HOST=\"embedded\" # comment\n")},
+	}
+
+	t.Setenv("ENVFS_HOST", "env")
+	t.Cleanup(func() {
+		_ = os.Unsetenv("ENVFS_PORT")
+	})
+
+	err := sc.LoadEnvFS(fsys, "defaults/.env", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port, err := sc.GetInt("PORT")
+	if err != nil || port != 8080 {
+		t.Fatalf("expected value from the embedded file, received: %v, %v", port, err)
+	}
+
+	host, err := sc.GetString("HOST")
+	if err != nil || host != "env" {
+		t.Fatalf("expected existing variable to be kept, received: %v, %v", host, err)
+	}
+
+	err = sc.LoadEnvFS(fsys, "missing/.env", false)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected fs.ErrNotExist, received: %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetDuration returns the config value parsed with time.ParseDuration, e.g. "1m30s".
+func (sc ServiceConfig) GetDuration(name string, opts ...GetOption) (time.Duration, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return 0, ErrConfigNotFound
+	}
+	return time.ParseDuration(configData)
+}
+
+func (sc ServiceConfig) GetDurationWithDefault(name string, defaultValue time.Duration, opts ...GetOption) (time.Duration, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return defaultValue, nil
+	}
+	return time.ParseDuration(configData)
+}
+
+// GetDurationArray returns the config value split using ArraySeparator, with every element parsed independently
+// with time.ParseDuration. Elements may use different units, e.g. "100ms 1s 5m". An element without a unit, other
+// than "0", is an error.
+func (sc ServiceConfig) GetDurationArray(name string, opts ...GetOption) ([]time.Duration, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+
+	return sc.parseDurationArray(name, configData, 0)
+}
+
+// GetDurationArrayUnit is like GetDurationArray, but elements that are bare numbers are interpreted in unit, e.g.
+// "30 1m 90" with unit time.Second is 30 seconds, 1 minute and 90 seconds. It mirrors the `unit` tag option.
+func (sc ServiceConfig) GetDurationArrayUnit(name string, unit time.Duration, opts ...GetOption) ([]time.Duration, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+
+	return sc.parseDurationArray(name, configData, unit)
+}
+
+// parseDurationArray parses every element of configData with parseDuration.
+func (sc ServiceConfig) parseDurationArray(name string, configData string, unit time.Duration) ([]time.Duration, error) {
+	configDataArray, err := sc.split(configData)
+	if err != nil {
+		return nil, err
+	}
+
+	durations := make([]time.Duration, 0, len(configDataArray))
+	for i, v := range configDataArray {
+		d, err := parseDuration(v, unit)
+		if err != nil {
+			return nil, fmt.Errorf("config name %s element %d cannot be parsed: %w", name, i, err)
+		}
+		durations = append(durations, d)
+	}
+
+	return durations, nil
+}
+
+// parseDuration parses s with time.ParseDuration. When unit is not zero, s may also be a bare decimal number, which
+// is interpreted in unit, e.g. "30" with unit time.Second is 30 seconds. Other notations accepted by strconv, such as
+// exponents, hexadecimal, "NaN" or "Inf", are not bare numbers, and durations beyond the range of time.Duration are
+// an error.
+func parseDuration(s string, unit time.Duration) (time.Duration, error) {
+	if unit != 0 && bareNumberRegexp.MatchString(s) {
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		d := n * float64(unit)
+		if d >= math.MaxInt64 || d < math.MinInt64 {
+			return 0, fmt.Errorf("invalid duration %q: out of range", s)
+		}
+		return time.Duration(d), nil
+	}
+
+	return time.ParseDuration(s)
+}
+
+var bareNumberRegexp = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+
+// GetClockDuration returns the config value parsed as a clock time, HH:MM:SS or MM:SS, e.g. "01:30:00" for 90
+// minutes, rather than with time.ParseDuration. ParseTo does the same for time.Duration fields tagged with the
+// `clock` option, e.g. `config:"RETENTION,clock"`.
+func (sc ServiceConfig) GetClockDuration(name string, opts ...GetOption) (time.Duration, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return 0, ErrConfigNotFound
+	}
+
+	d, err := parseClock(configData)
+	if err != nil {
+		return 0, sc.reformatParseError(name, err)
+	}
+	return d, nil
+}
+
+// parseClock parses s as HH:MM:SS or MM:SS. The first component may be any number of digits, while the following
+// ones must be two digits below 60, e.g. "100:00:00" is 100 hours, but "01:60" is an error. Components are unsigned,
+// so negative durations such as "-0:30" are an error.
+func parseClock(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 && len(parts) != 3 {
+		return 0, fmt.Errorf("invalid clock duration %q, expected HH:MM:SS or MM:SS", s)
+	}
+
+	var total time.Duration
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || p[0] < '0' || p[0] > '9' || (i > 0 && (len(p) != 2 || n >= 60)) {
+			return 0, fmt.Errorf("invalid clock duration %q, expected HH:MM:SS or MM:SS", s)
+		}
+		total = total*60 + time.Duration(n)
+	}
+
+	return total * time.Second, nil
+}
+
+// parseUnit parses the `unit` option of a duration field, such as "s" or "ms".
+func parseUnit(opts tagOptions) (time.Duration, error) {
+	u, ok := opts.get("unit")
+	if !ok {
+		return 0, nil
+	}
+
+	unit, err := time.ParseDuration("1" + u)
+	if err != nil {
+		return 0, fmt.Errorf("invalid unit option `%s`: %w", u, err)
+	}
+
+	return unit, nil
+}
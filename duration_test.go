@@ -0,0 +1,165 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServiceConfig_GetDurationArray(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "DURATION",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("DURATION_STAGES", "100ms 1s 5m 1h30m")
+	stages, err := sc.GetDurationArray("STAGES")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []time.Duration{100 * time.Millisecond, time.Second, 5 * time.Minute, 90 * time.Minute}
+	if !reflect.DeepEqual(expect, stages) {
+		t.Fatalf("parsed array is not the same with expectation, received: %v, expected: %v", stages, expect)
+	}
+
+	t.Setenv("DURATION_BARE", "100ms 30")
+	_, err = sc.GetDurationArray("BARE")
+	if err == nil || !strings.Contains(err.Error(), "BARE element 1") {
+		t.Fatalf("expected error for bare number naming the key and index, received: %v", err)
+	}
+
+	type TestConfig struct {
+		Stages  []time.Duration `config:"STAGES"`
+		Bare    []time.Duration `config:"BARE,unit=s"`
+		Timeout time.Duration   `config:"TIMEOUT,default=2m"`
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectConfig := &TestConfig{
+		Stages:  expect,
+		Bare:    []time.Duration{100 * time.Millisecond, 30 * time.Second},
+		Timeout: 2 * time.Minute,
+	}
+	if !reflect.DeepEqual(expectConfig, n) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expectConfig)
+	}
+}
+
+func TestServiceConfig_GetDurationArrayUnit(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "DURATIONUNIT",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("DURATIONUNIT_RETRIES", "30 2m 1.5")
+	t.Setenv("DURATIONUNIT_BROKEN", "30 soon")
+
+	v, err := sc.GetDurationArrayUnit("RETRIES", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []time.Duration{30 * time.Second, 2 * time.Minute, 1500 * time.Millisecond}
+	if !reflect.DeepEqual(v, expected) {
+		t.Fatalf("unexpected durations, received: %v, expected: %v", v, expected)
+	}
+
+	_, err = sc.GetDurationArrayUnit("BROKEN", time.Second)
+	if err == nil || !strings.Contains(err.Error(), "BROKEN element 1") {
+		t.Fatalf("expected error with index and key, received: %v", err)
+	}
+}
+
+func TestServiceConfig_GetDurationArrayUnit_invalidNumbers(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "DURATIONNUM",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("DURATIONNUM_SIGNED", "-30 +.5")
+	v, err := sc.GetDurationArrayUnit("SIGNED", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []time.Duration{-30 * time.Second, 500 * time.Millisecond}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("unexpected durations, received: %v, expected: %v", v, expected)
+	}
+
+	for _, value := range []string{"NaN", "Inf", "-inf", "1e3", "0x10", "1e300", "9223372037"} {
+		t.Setenv("DURATIONNUM_BROKEN", value)
+		_, err := sc.GetDurationArrayUnit("BROKEN", time.Second)
+		if err == nil || !strings.Contains(err.Error(), "BROKEN element 0") {
+			t.Fatalf("%q: expected error with index and key, received: %v", value, err)
+		}
+	}
+}
+
+func TestServiceConfig_GetClockDuration(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix: "CLOCK",
+	}
+
+	tests := map[string]time.Duration{
+		"01:30:00":  90 * time.Minute,
+		"00:00:05":  5 * time.Second,
+		"100:00:00": 100 * time.Hour,
+		"02:15":     2*time.Minute + 15*time.Second,
+		"90:00":     90 * time.Minute,
+	}
+	for value, expect := range tests {
+		t.Setenv("CLOCK_RETENTION", value)
+		d, err := sc.GetClockDuration("RETENTION")
+		if err != nil {
+			t.Fatalf("%s: %v", value, err)
+		}
+		if d != expect {
+			t.Fatalf("%s: parsed duration is not the same with expectation, received: %v, expected: %v", value, d, expect)
+		}
+	}
+
+	for _, value := range []string{"", "1h30m", "01", "01:60", "01:5", "1:00:00:00", "-01:00", "-0:30", "+0:30", "01:-1", "aa:00"} {
+		t.Setenv("CLOCK_RETENTION", value)
+		_, err := sc.GetClockDuration("RETENTION")
+		if err == nil || !strings.Contains(err.Error(), "CLOCK_RETENTION") {
+			t.Fatalf("%q: expected error naming the key, received: %v", value, err)
+		}
+	}
+
+	_, err := sc.GetClockDuration("MISSING")
+	if err != ErrConfigNotFound {
+		t.Fatalf("expected ErrConfigNotFound, received: %v", err)
+	}
+
+	type TestConfig struct {
+		Retention time.Duration `config:"RETENTION,clock"`
+		Window    time.Duration `config:"WINDOW,clock,default=00:30"`
+	}
+
+	t.Setenv("CLOCK_RETENTION", "12:00:00")
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectConfig := &TestConfig{
+		Retention: 12 * time.Hour,
+		Window:    30 * time.Second,
+	}
+	if !reflect.DeepEqual(expectConfig, n) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expectConfig)
+	}
+
+	t.Setenv("CLOCK_RETENTION", "12h")
+	err = sc.ParseTo(&TestConfig{})
+	if err == nil || !strings.Contains(err.Error(), "CLOCK_RETENTION") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+}
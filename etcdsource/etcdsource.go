@@ -0,0 +1,225 @@
+// Package etcdsource provides a watchable config.Source reading from etcd. It is kept in its own module so that the
+// etcd dependencies are only required by services that use it.
+package etcdsource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	config "github.com/potatobeansco/go-config"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Client is the part of the etcd client used by the source. It is satisfied by *clientv3.Client.
+type Client interface {
+	clientv3.KV
+	clientv3.Watcher
+}
+
+const (
+	minBackoff = 100 * time.Millisecond
+	maxBackoff = 30 * time.Second
+)
+
+// Source is a config.WatchableSource reading all keys under an etcd prefix. Values are loaded once when the Source
+// is created and then kept up to date through an etcd watch, so lookups never block on etcd.
+//
+// When the watch fails, e.g. because the connection to etcd is lost, the error is reported to the functions
+// registered with OnError, and the Source retries with an exponential backoff. On every retry the keys are reloaded
+// and the OnChange functions are called for the keys that changed in the meantime. Until then, lookups return the last
+// known values.
+type Source struct {
+	client Client
+	prefix string
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu       sync.RWMutex
+	values   map[string]string
+	onChange []func(key string)
+	onError  []func(err error)
+}
+
+// NewEtcdSource loads all keys under prefix from etcd through client and starts watching them. Config keys are the
+// etcd keys with prefix stripped, e.g. with prefix "/config/" the etcd key "/config/MYAPP_PORT" provides MYAPP_PORT.
+//
+// The Source must be closed with Close to stop watching.
+func NewEtcdSource(client Client, prefix string) (*Source, error) {
+	if client == nil {
+		return nil, errors.New("etcdsource: client is nil")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Source{
+		client: client,
+		prefix: prefix,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		values: make(map[string]string),
+	}
+
+	rev, err := s.load(ctx)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("etcdsource: cannot load %s: %w", prefix, err)
+	}
+
+	go s.watch(ctx, rev)
+	return s, nil
+}
+
+func (s *Source) Lookup(key string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, found := s.values[key]
+	return value, found, nil
+}
+
+func (s *Source) OnChange(fn func(key string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onChange = append(s.onChange, fn)
+}
+
+// OnError registers fn to be called with every error that interrupts the watch. fn is called from another
+// goroutine.
+func (s *Source) OnError(fn func(err error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onError = append(s.onError, fn)
+}
+
+// Close stops watching etcd. The client is not closed.
+func (s *Source) Close() error {
+	s.cancel()
+	<-s.done
+	return nil
+}
+
+// String returns the name of the source, used when reporting where a config came from.
+func (s *Source) String() string {
+	return "etcd:" + s.prefix
+}
+
+// load replaces all values with the ones currently in etcd, notifies about the keys that differ, and returns the
+// revision the values were read at.
+func (s *Source) load(ctx context.Context) (int64, error) {
+	resp, err := s.client.Get(ctx, s.prefix, clientv3.WithPrefix())
+	if err != nil {
+		return 0, err
+	}
+
+	values := make(map[string]string, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		values[strings.TrimPrefix(string(kv.Key), s.prefix)] = string(kv.Value)
+	}
+
+	s.mu.Lock()
+	changed := make([]string, 0)
+	for key, value := range values {
+		if old, ok := s.values[key]; !ok || old != value {
+			changed = append(changed, key)
+		}
+	}
+	for key := range s.values {
+		if _, ok := values[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	s.values = values
+	s.mu.Unlock()
+
+	s.notify(changed...)
+	return resp.Header.Revision, nil
+}
+
+func (s *Source) watch(ctx context.Context, rev int64) {
+	defer close(s.done)
+
+	backoff := minBackoff
+	for {
+		err := s.watchFrom(ctx, rev)
+		if ctx.Err() != nil {
+			return
+		}
+		s.report(err)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			backoff = min(backoff*2, maxBackoff)
+			rev, err = s.load(ctx)
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			s.report(err)
+		}
+
+		backoff = minBackoff
+	}
+}
+
+// watchFrom applies the changes after rev until the watch fails.
+func (s *Source) watchFrom(ctx context.Context, rev int64) error {
+	ctx = clientv3.WithRequireLeader(ctx)
+	for resp := range s.client.Watch(ctx, s.prefix, clientv3.WithPrefix(), clientv3.WithRev(rev+1)) {
+		if err := resp.Err(); err != nil {
+			return err
+		}
+
+		changed := make([]string, 0, len(resp.Events))
+		s.mu.Lock()
+		for _, event := range resp.Events {
+			key := strings.TrimPrefix(string(event.Kv.Key), s.prefix)
+			if event.Type == clientv3.EventTypeDelete {
+				delete(s.values, key)
+			} else {
+				s.values[key] = string(event.Kv.Value)
+			}
+			changed = append(changed, key)
+		}
+		s.mu.Unlock()
+
+		s.notify(changed...)
+	}
+
+	return errors.New("etcdsource: watch closed")
+}
+
+func (s *Source) notify(keys ...string) {
+	s.mu.RLock()
+	callbacks := s.onChange
+	s.mu.RUnlock()
+
+	for _, key := range keys {
+		for _, fn := range callbacks {
+			fn(key)
+		}
+	}
+}
+
+func (s *Source) report(err error) {
+	s.mu.RLock()
+	callbacks := s.onError
+	s.mu.RUnlock()
+
+	for _, fn := range callbacks {
+		fn(fmt.Errorf("etcdsource: watch %s: %w", s.prefix, err))
+	}
+}
+
+var _ config.WatchableSource = (*Source)(nil)
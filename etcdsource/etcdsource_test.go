@@ -0,0 +1,113 @@
+package etcdsource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+type fakeClient struct {
+	clientv3.KV
+	clientv3.Watcher
+
+	kvs     []*mvccpb.KeyValue
+	watches chan chan clientv3.WatchResponse
+}
+
+func (f *fakeClient) Get(context.Context, string, ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	return &clientv3.GetResponse{
+		Header: &etcdserverpb.ResponseHeader{Revision: 1},
+		Kvs:    f.kvs,
+	}, nil
+}
+
+func (f *fakeClient) Watch(ctx context.Context, _ string, _ ...clientv3.OpOption) clientv3.WatchChan {
+	ch := make(chan clientv3.WatchResponse)
+	f.watches <- ch
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch
+}
+
+func TestNewEtcdSource(t *testing.T) {
+	client := &fakeClient{
+		kvs: []*mvccpb.KeyValue{
+			{Key: []byte("/config/MYAPP_PORT"), Value: []byte("80")},
+		},
+		watches: make(chan chan clientv3.WatchResponse, 2),
+	}
+
+	source, err := NewEtcdSource(client, "/config/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Close()
+
+	value, found, err := source.Lookup("MYAPP_PORT")
+	if err != nil || !found || value != "80" {
+		t.Fatalf("unexpected lookup result: %q, %v, %v", value, found, err)
+	}
+
+	changes := make(chan string, 4)
+	source.OnChange(func(key string) {
+		changes <- key
+	})
+	errs := make(chan error, 1)
+	source.OnError(func(err error) {
+		errs <- err
+	})
+
+	watch := <-client.watches
+	watch <- clientv3.WatchResponse{Events: []*clientv3.Event{
+		{Type: clientv3.EventTypePut, Kv: &mvccpb.KeyValue{Key: []byte("/config/MYAPP_PORT"), Value: []byte("443")}},
+	}}
+
+	select {
+	case key := <-changes:
+		if key != "MYAPP_PORT" {
+			t.Fatalf("unexpected changed key: %s", key)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("change was not notified")
+	}
+
+	value, _, _ = source.Lookup("MYAPP_PORT")
+	if value != "443" {
+		t.Fatalf("expected watched value 443, received: %s", value)
+	}
+
+	// Losing the watch reloads the values from etcd, which reverts the port, and watches again.
+	watch <- clientv3.WatchResponse{CompactRevision: 2}
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Fatal("expected watch error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watch error was not reported")
+	}
+
+	select {
+	case <-client.watches:
+	case <-time.After(time.Second):
+		t.Fatal("watch was not retried")
+	}
+
+	value, _, _ = source.Lookup("MYAPP_PORT")
+	if value != "80" {
+		t.Fatalf("expected reloaded value 80, received: %s", value)
+	}
+}
+
+func TestNewEtcdSource_nilClient(t *testing.T) {
+	_, err := NewEtcdSource(nil, "/config/")
+	if err == nil {
+		t.Fatalf("expected error for nil client, received: %v", err)
+	}
+}
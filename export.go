@@ -0,0 +1,172 @@
+package config
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportEnv returns the current values of the struct pointed by obj as NAME=VALUE assignments with the full config
+// names, including the prefix, e.g. to pass the effective configuration to a child process through exec.Cmd.Env.
+// Values are written so that ParseTo reads them back, using the inverse of the options of each field:
+//
+//   - slices are joined with ArraySeparator, or the `sep` option, with every element written with the options of the
+//     field, e.g. its `layout`, and integer slices in the base of their `base` option;
+//   - string slices tagged with the `csv` option are written as a CSV record, quoting elements as needed;
+//   - maps are written as key-value pairs;
+//   - fields tagged with the `json` option, and []map fields, are written as JSON, as are types implementing
+//     json.Unmarshaler but not encoding.TextMarshaler;
+//   - byte slices are written in base64, or hex with the `hex` option;
+//   - time.Time is written with its `layout` option, or RFC 3339 with nanoseconds;
+//   - durations tagged with the `clock` option are written as HH:MM:SS, truncated to whole seconds;
+//   - types implementing encoding.TextMarshaler are written with MarshalText, and other types implementing
+//     fmt.Stringer, such as *time.Location or *regexp.Regexp, with String;
+//   - fields tagged with the `chunked` option are written whole to their first chunk, e.g. NAME_1.
+//
+// Computed fields are left out, since they have no config name. Fields tagged with the `encrypted` option are written
+// as decrypted, so they are not read back as they are.
+//
+// Values of fields tagged with the `secure` option are included as they are, so the result holds secrets and must
+// not be logged.
+func (sc ServiceConfig) ExportEnv(obj interface{}) []string {
+	assertPointer(obj)
+	sc = sc.withStructSettings(obj)
+
+	env := make([]string, 0)
+	for _, f := range configFields(obj) {
+		if f.name == "" || f.name == "-" {
+			continue
+		}
+
+		key := sc.getConfigName(f.name)
+		if f.opts.has("chunked") {
+			key = sc.chunkKey(f.name, 1)
+		}
+
+		env = append(env, key+"="+sc.withOptions(f.opts.getOptions()).formatValue(f.value, f.opts))
+	}
+
+	return env
+}
+
+// formatValue formats v the way it would be configured for a field with the options opts.
+func (sc ServiceConfig) formatValue(v reflect.Value, opts tagOptions) string {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return ""
+	}
+
+	if opts.has("json") {
+		return formatJSON(v)
+	}
+
+	switch val := v.Interface().(type) {
+	case time.Time:
+		name, _ := opts.get("layout")
+		layout := time.RFC3339Nano
+		if name != "" {
+			l, err := timeLayout(name)
+			if err == nil {
+				layout = l
+			}
+		}
+		return val.Format(layout)
+	case time.Duration:
+		if opts.has("clock") {
+			return formatClock(val)
+		}
+	case []byte:
+		return formatBytes(val, opts)
+	case []map[string]string, []map[string]interface{}:
+		return formatJSON(v)
+	case encoding.TextMarshaler:
+		text, err := val.MarshalText()
+		if err == nil {
+			return string(text)
+		}
+	}
+
+	if reflect.PointerTo(v.Type()).Implements(jsonUnmarshalerType) {
+		return formatJSON(v)
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		return sc.formatValue(v.Elem(), opts)
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return formatBytes(b, opts)
+		}
+	case reflect.Slice:
+		if opts.has("csv") && v.Type().Elem().Kind() == reflect.String {
+			record := make([]string, v.Len())
+			for i := range record {
+				record[i] = v.Index(i).String()
+			}
+			return formatCSV(record)
+		}
+
+		base := 10
+		if b, ok := opts.get("base"); ok {
+			if n, err := strconv.Atoi(b); err == nil {
+				base = n
+			}
+		}
+
+		elems := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			elem := v.Index(i)
+			if base != 10 && elem.Kind() == reflect.Int {
+				elems = append(elems, strconv.FormatInt(elem.Int(), base))
+				continue
+			}
+			elems = append(elems, sc.formatValue(elem, opts))
+		}
+		return strings.Join(elems, sc.ArraySeparator)
+	case reflect.Map:
+		pairs := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			pairs = append(pairs, sc.formatValue(k, nil)+"="+sc.formatValue(v.MapIndex(k), nil))
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, sc.ArraySeparator)
+	}
+
+	return formatScalar(v.Interface())
+}
+
+// formatJSON encodes the value of v in JSON, or formats it with %v if it cannot be encoded.
+func formatJSON(v reflect.Value) string {
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return fmt.Sprintf("%v", v.Interface())
+	}
+
+	return string(b)
+}
+
+// formatClock formats d as HH:MM:SS, the inverse of parseClock. Fractions of a second are truncated.
+func formatClock(d time.Duration) string {
+	seconds := int64(d / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+// formatBytes encodes b in hex when opts has the `hex` option, or in standard base64 otherwise.
+func formatBytes(b []byte, opts tagOptions) string {
+	if opts.has("hex") {
+		return hex.EncodeToString(b)
+	}
+
+	return base64.StdEncoding.EncodeToString(b)
+}
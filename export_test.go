@@ -0,0 +1,219 @@
+package config
+
+import (
+	"os/exec"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServiceConfig_ExportEnv(t *testing.T) {
+	type TestConfig struct {
+		Host     string            `config:"HOST"`
+		Port     int               `config:"PORT"`
+		Ratio    float64           `config:"RATIO"`
+		Timeout  time.Duration     `config:"TIMEOUT"`
+		Hosts    []string          `config:"HOSTS"`
+		Headers  map[string]string `config:"HEADERS"`
+		Key      []byte            `config:"KEY,hex"`
+		Since    time.Time         `config:"SINCE,layout=dateonly"`
+		Level    testLevel         `config:"LEVEL"`
+		Password string            `config:"PASSWORD,secure"`
+		URL      string            `config:"-,compute=http://{HOST}:{PORT}"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "EXPORTENV",
+		ArraySeparator: ",",
+	}
+
+	cfg := &TestConfig{
+		Host:     "localhost",
+		Port:     8080,
+		Ratio:    0.25,
+		Timeout:  90 * time.Second,
+		Hosts:    []string{"a", "b"},
+		Headers:  map[string]string{"b": "2", "a": "1"},
+		Key:      []byte{0xde, 0xad},
+		Since:    time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Level:    testLevelWarn,
+		Password: "hunter2",
+		URL:      "http://localhost:8080",
+	}
+
+	env := sc.ExportEnv(cfg)
+	expected := []string{
+		"EXPORTENV_HOST=localhost",
+		"EXPORTENV_PORT=8080",
+		"EXPORTENV_RATIO=0.25",
+		"EXPORTENV_TIMEOUT=1m30s",
+		"EXPORTENV_HOSTS=a,b",
+		"EXPORTENV_HEADERS=a=1,b=2",
+		"EXPORTENV_KEY=dead",
+		"EXPORTENV_SINCE=2024-05-01",
+		"EXPORTENV_LEVEL=WARN",
+		"EXPORTENV_PASSWORD=hunter2",
+	}
+	if !reflect.DeepEqual(env, expected) {
+		t.Fatalf("exported environment is not the same with expectation, received: %v, expected: %v", env, expected)
+	}
+
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		t.Setenv(key, value)
+	}
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(n, cfg) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, cfg)
+	}
+
+	out, err := exec.Command("sh", "-c", "echo $EXPORTENV_HOSTS").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(out)) != "a,b" {
+		t.Fatalf("expected the child process to see the config, received: %s", out)
+	}
+}
+
+type exportPoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func TestServiceConfig_ExportEnv_roundTrip(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "EXPORTRT",
+		ArraySeparator: ",",
+	}
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type JSONConfig struct {
+		Point  exportPoint    `config:"POINT,json"`
+		Limits map[string]int `config:"LIMITS,json"`
+	}
+	type MapsConfig struct {
+		Routes []map[string]string `config:"ROUTES"`
+	}
+	type ChunkedConfig struct {
+		Cert string `config:"CERT,chunked"`
+	}
+	type ClockConfig struct {
+		Retention time.Duration `config:"RETENTION,clock"`
+	}
+	type LocationConfig struct {
+		TZ *time.Location `config:"TZ"`
+	}
+	type RegexpConfig struct {
+		Pattern *regexp.Regexp `config:"PATTERN"`
+	}
+	type BaseConfig struct {
+		Masks []int `config:"MASKS,base=16"`
+	}
+	type TimeConfig struct {
+		At time.Time `config:"AT"`
+	}
+
+	tests := map[string]interface{}{
+		"json":     &JSONConfig{Point: exportPoint{X: 1, Y: 2}, Limits: map[string]int{"a": 1}},
+		"maps":     &MapsConfig{Routes: []map[string]string{{"path": "/a"}, {"path": "/b"}}},
+		"chunked":  &ChunkedConfig{Cert: "-----BEGIN CERTIFICATE-----"},
+		"clock":    &ClockConfig{Retention: 100*time.Hour + 2*time.Minute + 3*time.Second},
+		"location": &LocationConfig{TZ: berlin},
+		"regexp":   &RegexpConfig{Pattern: regexp.MustCompile(`^a+,b$`)},
+		"base":     &BaseConfig{Masks: []int{255, 16}},
+		"time":     &TimeConfig{At: time.Date(2024, 5, 1, 2, 3, 4, 500, time.UTC)},
+	}
+
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			env := sc.ExportEnv(cfg)
+			for _, kv := range env {
+				key, value, _ := strings.Cut(kv, "=")
+				t.Setenv(key, value)
+			}
+
+			n := reflect.New(reflect.TypeOf(cfg).Elem()).Interface()
+			err := sc.ParseTo(n)
+			if err != nil {
+				t.Fatalf("cannot parse %v: %v", env, err)
+			}
+
+			if reexported := sc.ExportEnv(n); !reflect.DeepEqual(reexported, env) {
+				t.Fatalf("exported environment is not the same after parsing, received: %v, expected: %v", reexported, env)
+			}
+			if name != "location" && name != "regexp" && !reflect.DeepEqual(n, cfg) {
+				t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, cfg)
+			}
+		})
+	}
+}
+
+func TestServiceConfig_ExportEnv_elementOptions(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "EXPORTELEM",
+		ArraySeparator: " ",
+	}
+
+	type SepConfig struct {
+		Hosts []string `config:"HOSTS,sep=;"`
+	}
+	type LayoutConfig struct {
+		Days []time.Time `config:"DAYS,layout=dateonly"`
+	}
+	type CSVConfig struct {
+		Columns []string `config:"COLUMNS,csv"`
+	}
+
+	tests := map[string]struct {
+		cfg      interface{}
+		expected []string
+	}{
+		"sep": {
+			cfg:      &SepConfig{Hosts: []string{"a b", "c"}},
+			expected: []string{"EXPORTELEM_HOSTS=a b;c"},
+		},
+		"layout": {
+			cfg:      &LayoutConfig{Days: []time.Time{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}},
+			expected: []string{"EXPORTELEM_DAYS=2024-01-01 2024-02-01"},
+		},
+		"csv": {
+			cfg:      &CSVConfig{Columns: []string{"a", "b c", `say "hi", bye`}},
+			expected: []string{`EXPORTELEM_COLUMNS=a,b c,"say ""hi"", bye"`},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			env := sc.ExportEnv(test.cfg)
+			if !reflect.DeepEqual(env, test.expected) {
+				t.Fatalf("exported environment is not the same with expectation, received: %v, expected: %v", env, test.expected)
+			}
+
+			for _, kv := range env {
+				key, value, _ := strings.Cut(kv, "=")
+				t.Setenv(key, value)
+			}
+
+			n := reflect.New(reflect.TypeOf(test.cfg).Elem()).Interface()
+			err := sc.ParseTo(n)
+			if err != nil {
+				t.Fatalf("cannot parse %v: %v", env, err)
+			}
+			if !reflect.DeepEqual(n, test.cfg) {
+				t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, test.cfg)
+			}
+		})
+	}
+}
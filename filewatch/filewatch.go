@@ -0,0 +1,131 @@
+// Package filewatch notifies when a config file changes, e.g. to reload a file loaded with config.LoadEnvFile or
+// config.NewJSONSource. It is kept in its own module so that the fsnotify dependency is only required by services
+// that use it.
+package filewatch
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Debounce is how long a Watcher waits after the last change to a file before calling its callback, so that editors
+// writing a file several times in a row trigger a single reload.
+const Debounce = 100 * time.Millisecond
+
+// Watcher watches a single file, see WatchFile.
+type Watcher struct {
+	watcher *fsnotify.Watcher
+	name    string
+	target  string
+	done    chan struct{}
+
+	mu    sync.Mutex
+	timer *time.Timer
+}
+
+// WatchFile calls onChange whenever the file at path is written, created, removed or renamed, at most once per
+// Debounce. The directory of the file is watched rather than the file itself, so that updates replacing the file
+// with an atomic rename, as done by many editors, keep being noticed. When path is a symbolic link, onChange is also
+// called when the file it resolves to changes because a link in the directory was replaced, as done by Kubernetes
+// for mounted ConfigMaps and Secrets, which swap the ..data link to a new directory.
+// onChange is called from another goroutine.
+//
+// The Watcher must be closed with Close to stop watching. To be told about errors of the underlying watcher, such as
+// dropped events, use WatchFileWithErrors.
+func WatchFile(path string, onChange func()) (*Watcher, error) {
+	return WatchFileWithErrors(path, onChange, nil)
+}
+
+// WatchFileWithErrors is like WatchFile, but calls onError, when it is not nil, with every error reported by the
+// underlying watcher, e.g. when events were dropped, in which case the file may have changed unnoticed. onError is
+// called from another goroutine.
+func WatchFileWithErrors(path string, onChange func(), onError func(err error)) (*Watcher, error) {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("filewatch: cannot create watcher: %w", err)
+	}
+
+	name := filepath.Clean(path)
+	err = fw.Add(filepath.Dir(name))
+	if err != nil {
+		_ = fw.Close()
+		return nil, fmt.Errorf("filewatch: cannot watch %s: %w", path, err)
+	}
+
+	w := &Watcher{
+		watcher: fw,
+		name:    name,
+		target:  resolve(name),
+		done:    make(chan struct{}),
+	}
+
+	go w.listen(onChange, onError)
+	return w, nil
+}
+
+// Close stops watching. A pending callback is cancelled, and no callback is started after Close returns.
+func (w *Watcher) Close() error {
+	err := w.watcher.Close()
+	<-w.done
+
+	w.mu.Lock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mu.Unlock()
+
+	return err
+}
+
+func (w *Watcher) listen(onChange func(), onError func(err error)) {
+	defer close(w.done)
+
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			// Any change in the directory may swap a link that path goes through, so the target is resolved again.
+			target := resolve(w.name)
+			changed := filepath.Clean(event.Name) == w.name || target != w.target
+			w.target = target
+			if !changed {
+				continue
+			}
+
+			w.mu.Lock()
+			if w.timer != nil {
+				w.timer.Stop()
+			}
+			w.timer = time.AfterFunc(Debounce, onChange)
+			w.mu.Unlock()
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			if onError != nil {
+				onError(fmt.Errorf("filewatch: %s: %w", w.name, err))
+			}
+		}
+	}
+}
+
+// resolve returns the path that name resolves to through symbolic links, or an empty string when it cannot be
+// resolved, e.g. because the file was removed.
+func resolve(name string) string {
+	target, err := filepath.EvalSymlinks(name)
+	if err != nil {
+		return ""
+	}
+
+	return target
+}
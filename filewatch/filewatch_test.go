@@ -0,0 +1,143 @@
+package filewatch
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatchFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.env")
+	err := os.WriteFile(path, []byte("MYAPP_PORT=8080\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls atomic.Int32
+	w, err := WatchFile(path, func() {
+		calls.Add(1)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	err = os.WriteFile(filepath.Join(dir, "other.env"), []byte("x"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		err = os.WriteFile(path, []byte("MYAPP_PORT=9090\n"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitCalls(t, &calls, 1)
+
+	tmp := filepath.Join(dir, "app.env.tmp")
+	err = os.WriteFile(tmp, []byte("MYAPP_PORT=7070\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitCalls(t, &calls, 2)
+
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path, []byte("MYAPP_PORT=6060\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(3 * Debounce)
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("expected no call after Close, received %d calls", n)
+	}
+}
+
+// waitCalls waits for calls to reach expected, and fails when it does not or when it goes beyond.
+func waitCalls(t *testing.T, calls *atomic.Int32, expected int32) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for calls.Load() < expected && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(3 * Debounce)
+	if n := calls.Load(); n != expected {
+		t.Fatalf("expected %d calls, received %d", expected, n)
+	}
+}
+
+func TestWatchFile_symlinkSwap(t *testing.T) {
+	// The layout of a ConfigMap mounted by Kubernetes: the file is a link into ..data, itself a link to a
+	// timestamped directory, and updates atomically replace ..data.
+	dir := t.TempDir()
+	writeVersion := func(version, content string) {
+		t.Helper()
+		err := os.Mkdir(filepath.Join(dir, version), 0755)
+		if err == nil {
+			err = os.WriteFile(filepath.Join(dir, version, "app.env"), []byte(content), 0644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeVersion("..2024_01", "MYAPP_PORT=8080\n")
+	err := os.Symlink("..2024_01", filepath.Join(dir, "..data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "app.env")
+	err = os.Symlink(filepath.Join("..data", "app.env"), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls atomic.Int32
+	var errs atomic.Int32
+	w, err := WatchFileWithErrors(path, func() {
+		calls.Add(1)
+	}, func(err error) {
+		errs.Add(1)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	writeVersion("..2024_02", "MYAPP_PORT=9090\n")
+	err = os.Symlink("..2024_02", filepath.Join(dir, "..data_tmp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.RemoveAll(filepath.Join(dir, "..2024_01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitCalls(t, &calls, 1)
+
+	err = os.WriteFile(filepath.Join(dir, "unrelated"), []byte("x"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(3 * Debounce)
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("expected no call for an unrelated file, received %d calls", n)
+	}
+	if n := errs.Load(); n != 0 {
+		t.Fatalf("expected no error, received %d", n)
+	}
+}
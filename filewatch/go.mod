@@ -0,0 +1,7 @@
+module github.com/potatobeansco/go-config/filewatch
+
+go 1.23.0
+
+require github.com/fsnotify/fsnotify v1.10.1
+
+require golang.org/x/sys v0.13.0 // indirect
@@ -0,0 +1,108 @@
+// Package gcpsecret provides a config.Source reading from Google Cloud Secret Manager. It is kept in its own module
+// so that the Google Cloud dependencies are only required by services that use it.
+package gcpsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"github.com/googleapis/gax-go/v2"
+	config "github.com/potatobeansco/go-config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SecretAccessor is the part of the Secret Manager client used by the source. It is satisfied by
+// *secretmanager.Client.
+type SecretAccessor interface {
+	AccessSecretVersion(ctx context.Context, req *secretmanagerpb.AccessSecretVersionRequest, opts ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error)
+}
+
+// ErrAccess is wrapped by errors returned when a secret exists but cannot be accessed, e.g. because of missing
+// permissions or a disabled version. Secrets that do not exist are not errors, but are reported as not found.
+var ErrAccess = errors.New("cannot access secret")
+
+// Source is a config.Source that resolves each config key to the secret with the same ID in a project.
+type Source struct {
+	client  SecretAccessor
+	project string
+
+	mu    sync.Mutex
+	cache map[string]entry
+}
+
+type entry struct {
+	value string
+	found bool
+}
+
+// NewGCPSecretSource returns a Source reading secrets of project through client.
+//
+// A config key is resolved to the latest version of the secret with the same ID, e.g. the key MYAPP_DB_PASSWORD to
+// projects/<project>/secrets/MYAPP_DB_PASSWORD/versions/latest. A version can be pinned by appending it to the
+// config name after "@", e.g. `config:"DB_PASSWORD@3"`. Results, including missing secrets, are cached for the
+// lifetime of the Source.
+func NewGCPSecretSource(client SecretAccessor, project string) (config.Source, error) {
+	if client == nil {
+		return nil, errors.New("gcpsecret: client is nil")
+	}
+	if project == "" {
+		return nil, errors.New("gcpsecret: project is empty")
+	}
+
+	return &Source{
+		client:  client,
+		project: project,
+		cache:   make(map[string]entry),
+	}, nil
+}
+
+func (s *Source) Lookup(key string) (string, bool, error) {
+	return s.LookupContext(context.Background(), key)
+}
+
+// LookupContext is like Lookup, but the request to Secret Manager is cancelled when ctx is done.
+func (s *Source) LookupContext(ctx context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	e, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok {
+		return e.value, e.found, nil
+	}
+
+	secret, version, ok := strings.Cut(key, "@")
+	if !ok {
+		version = "latest"
+	}
+
+	resp, err := s.client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{
+		Name: fmt.Sprintf("projects/%s/secrets/%s/versions/%s", s.project, secret, version),
+	})
+	switch {
+	case err != nil && ctx.Err() != nil:
+		return "", false, fmt.Errorf("gcpsecret: cannot read %s: %w", key, ctx.Err())
+	case status.Code(err) == codes.NotFound:
+		e = entry{}
+	case err != nil:
+		return "", false, fmt.Errorf("%w %s: %w", ErrAccess, key, err)
+	default:
+		e = entry{value: string(resp.GetPayload().GetData()), found: true}
+	}
+
+	s.mu.Lock()
+	s.cache[key] = e
+	s.mu.Unlock()
+
+	return e.value, e.found, nil
+}
+
+// String returns the name of the source, used when reporting where a config came from.
+func (s *Source) String() string {
+	return "gcpsecret:" + s.project
+}
+
+var _ config.ContextSource = (*Source)(nil)
@@ -0,0 +1,107 @@
+package gcpsecret
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"github.com/googleapis/gax-go/v2"
+	config "github.com/potatobeansco/go-config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeAccessor struct {
+	secrets map[string]string
+	calls   int
+}
+
+func (f *fakeAccessor) AccessSecretVersion(ctx context.Context, req *secretmanagerpb.AccessSecretVersionRequest, _ ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error) {
+	f.calls++
+	if strings.Contains(req.Name, "SLOW") {
+		<-ctx.Done()
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	if strings.Contains(req.Name, "FORBIDDEN") {
+		return nil, status.Error(codes.PermissionDenied, "denied")
+	}
+
+	data, ok := f.secrets[req.Name]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+
+	return &secretmanagerpb.AccessSecretVersionResponse{
+		Payload: &secretmanagerpb.SecretPayload{Data: []byte(data)},
+	}, nil
+}
+
+func TestNewGCPSecretSource(t *testing.T) {
+	accessor := &fakeAccessor{secrets: map[string]string{
+		"projects/p/secrets/MYAPP_DB_PASSWORD/versions/latest": "latest",
+		"projects/p/secrets/MYAPP_DB_PASSWORD/versions/3":      "pinned",
+	}}
+
+	source, err := NewGCPSecretSource(accessor, "p")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc := config.ServiceConfig{
+		Prefix:         "MYAPP",
+		ArraySeparator: " ",
+		Sources:        []config.Source{source},
+	}
+
+	type TestConfig struct {
+		Password       string `config:"DB_PASSWORD"`
+		PinnedPassword string `config:"DB_PASSWORD@3"`
+		Missing        string `config:"MISSING"`
+	}
+
+	n := &TestConfig{Missing: "default"}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Password != "latest" || n.PinnedPassword != "pinned" || n.Missing != "default" {
+		t.Fatalf("unexpected decoded config: %+v", n)
+	}
+
+	calls := accessor.calls
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accessor.calls != calls {
+		t.Fatalf("expected cached lookups, received %d more calls", accessor.calls-calls)
+	}
+
+	_, err = sc.GetString("FORBIDDEN")
+	if !errors.Is(err, ErrAccess) {
+		t.Fatalf("expected access error, received: %v", err)
+	}
+}
+
+func TestSource_LookupContext(t *testing.T) {
+	source, err := NewGCPSecretSource(&fakeAccessor{}, "my-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc := config.ServiceConfig{
+		Prefix:  "MYAPP",
+		Sources: []config.Source{source},
+	}
+
+	type TestConfig struct {
+		Password string `config:"SLOW_PASSWORD,timeout=10ms"`
+	}
+
+	err = sc.ParseToCtx(context.Background(), &TestConfig{})
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "MYAPP_SLOW_PASSWORD") {
+		t.Fatalf("expected a deadline error naming the key, received: %v", err)
+	}
+}
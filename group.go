@@ -0,0 +1,61 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParseGroup is like ParseTo, but only parses the fields of the struct pointed by obj that are tagged with the `group`
+// option group, e.g. `config:"DB_HOST,group=db"` for the group "db", leaving every other field untouched. This lets
+// subsystems parse their part of a shared struct independently. Only the fields of the group may be reported as
+// missing, and the group must be either fully configured or not configured at all, as with ParseTo.
+func (sc ServiceConfig) ParseGroup(obj interface{}, group string) error {
+	assertPointer(obj)
+	if group == "" {
+		return errors.New("group name is empty")
+	}
+
+	return sc.parse(obj, &parseState{group: group})
+}
+
+// fieldGroups tracks which configs of every `group` tag option are configured, in the order the groups appear.
+type fieldGroups map[string]*fieldGroup
+
+type fieldGroup struct {
+	order int
+	set   []string
+	unset []string
+}
+
+// add records whether key, a member of group, is configured.
+func (g fieldGroups) add(group, key string, exist bool) {
+	fg, ok := g[group]
+	if !ok {
+		fg = &fieldGroup{order: len(g)}
+		g[group] = fg
+	}
+
+	if exist {
+		fg.set = append(fg.set, key)
+	} else {
+		fg.unset = append(fg.unset, key)
+	}
+}
+
+// check returns an error for the first group that is only partially configured.
+func (g fieldGroups) check() error {
+	var first string
+	for name, fg := range g {
+		if len(fg.set) > 0 && len(fg.unset) > 0 && (first == "" || fg.order < g[first].order) {
+			first = name
+		}
+	}
+	if first == "" {
+		return nil
+	}
+
+	fg := g[first]
+	return fmt.Errorf("group %s is partially configured: %s set, but %s not set", first,
+		strings.Join(fg.set, ", "), strings.Join(fg.unset, ", "))
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServiceConfig_ParseTo_group(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "GROUP",
+		ArraySeparator: " ",
+	}
+
+	type TestConfig struct {
+		DBHost   string `config:"DB_HOST,group=db"`
+		DBUser   string `config:"DB_USER,group=db"`
+		DBPass   string `config:"DB_PASS,group=db,secure"`
+		CacheURL string `config:"CACHE_URL,group=cache"`
+	}
+
+	err := sc.ParseTo(&TestConfig{})
+	if err != nil {
+		t.Fatalf("expected unset groups to be valid, received: %v", err)
+	}
+
+	t.Setenv("GROUP_DB_HOST", "localhost")
+	t.Setenv("GROUP_DB_USER", "app")
+
+	err = sc.ParseTo(&TestConfig{})
+	expected := "group db is partially configured: GROUP_DB_HOST, GROUP_DB_USER set, but GROUP_DB_PASS not set"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Fatalf("expected error %q, received: %v", expected, err)
+	}
+
+	t.Setenv("GROUP_DB_PASS", "hunter2")
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.DBHost != "localhost" || n.DBPass != "hunter2" {
+		t.Fatalf("unexpected decoded config: %v", n)
+	}
+}
+
+func TestServiceConfig_ParseGroup(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "PARSEGROUP",
+		ArraySeparator: " ",
+	}
+
+	type TestConfig struct {
+		DBHost   string `config:"DB_HOST,group=db,required"`
+		DBPort   int    `config:"DB_PORT,group=db"`
+		CacheURL string `config:"CACHE_URL,group=cache,required"`
+		Name     string `config:"NAME,required"`
+	}
+
+	t.Setenv("PARSEGROUP_DB_HOST", "localhost")
+	t.Setenv("PARSEGROUP_DB_PORT", "5432")
+	t.Setenv("PARSEGROUP_CACHE_URL", "redis://cache")
+
+	n := &TestConfig{Name: "kept"}
+	err := sc.ParseGroup(n, "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{DBHost: "localhost", DBPort: 5432, Name: "kept"}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+
+	os.Unsetenv("PARSEGROUP_DB_HOST")
+	os.Unsetenv("PARSEGROUP_DB_PORT")
+	var missing *MissingConfigError
+	err = sc.ParseGroup(n, "db")
+	if !errors.As(err, &missing) || !reflect.DeepEqual(missing.Keys, []string{"PARSEGROUP_DB_HOST"}) {
+		t.Fatalf("expected only the group keys to be missing, received: %v", err)
+	}
+
+	err = sc.ParseGroup(n, "")
+	if err == nil {
+		t.Fatal("expected an error for an empty group name")
+	}
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// ConfigHash returns a hex-encoded SHA-256 hash of the values of all config fields of the struct pointed by obj,
+// usually after ParseTo, e.g. to detect configuration drift between instances. The hash only depends on the config
+// names and values, in field declaration order, so equal configurations always have equal hashes.
+//
+// Values of fields tagged with the `secure` option are included, so that rotating a secret changes the hash, but
+// they are never returned or written anywhere. The hash of a low-entropy secret can still be guessed by trying
+// candidate values, so the hash should be treated as internal information.
+//
+// The error is reserved for values that cannot be hashed, and is currently always nil.
+func (sc ServiceConfig) ConfigHash(obj interface{}) (string, error) {
+	assertPointer(obj)
+
+	h := sha256.New()
+	for _, f := range configFields(obj) {
+		if f.name == "" {
+			continue
+		}
+
+		writeHashPart(h, f.key())
+		hashValue(h, f.value)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashValue writes v to w unambiguously: values implementing encoding.TextMarshaler or fmt.Stringer are written as
+// their text, since their internals, such as the caches of *time.Location or *regexp.Regexp, may differ between equal
+// values. Other pointers are followed, and elements of slices, arrays and maps are written one by one, with map
+// entries sorted by key.
+func hashValue(w io.Writer, v reflect.Value) {
+	for {
+		if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+			writeHashPart(w, "nil")
+			return
+		}
+		if text, ok := valueText(v); ok {
+			writeHashPart(w, text)
+			return
+		}
+		if v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface {
+			break
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		writeHashPart(w, "list"+strconv.Itoa(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			hashValue(w, v.Index(i))
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprintf("%v", keys[i].Interface()) < fmt.Sprintf("%v", keys[j].Interface())
+		})
+
+		writeHashPart(w, "map"+strconv.Itoa(len(keys)))
+		for _, k := range keys {
+			hashValue(w, k)
+			hashValue(w, v.MapIndex(k))
+		}
+	default:
+		writeHashPart(w, fmt.Sprintf("%v", v.Interface()))
+	}
+}
+
+// valueText returns the text of v if it implements encoding.TextMarshaler or fmt.Stringer.
+func valueText(v reflect.Value) (string, bool) {
+	if !v.CanInterface() {
+		return "", false
+	}
+
+	switch t := v.Interface().(type) {
+	case encoding.TextMarshaler:
+		text, err := t.MarshalText()
+		if err != nil {
+			return "", false
+		}
+		return "text:" + string(text), true
+	case fmt.Stringer:
+		return "text:" + t.String(), true
+	}
+
+	return "", false
+}
+
+// writeHashPart writes s to w prefixed with its length, so that no part can be mistaken for another. Writes to a
+// hash never fail.
+func writeHashPart(w io.Writer, s string) {
+	_, _ = io.WriteString(w, strconv.Itoa(len(s))+":"+s)
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestServiceConfig_ConfigHash(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "HASH",
+		ArraySeparator: " ",
+	}
+
+	type TestConfig struct {
+		Port     int            `config:"PORT"`
+		Hosts    []string       `config:"HOSTS"`
+		Location *time.Location `config:"TZ"`
+		Password string         `config:"PASSWORD,secure"`
+	}
+
+	hash := func(n *TestConfig) string {
+		h, err := sc.ConfigHash(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	a := hash(&TestConfig{Port: 8080, Hosts: []string{"a", "b"}, Location: time.UTC, Password: "hunter2"})
+	b := hash(&TestConfig{Port: 8080, Hosts: []string{"a", "b"}, Location: time.UTC, Password: "hunter2"})
+	if a != b || len(a) != 64 {
+		t.Fatalf("expected equal hashes for equal configs, received: %s, %s", a, b)
+	}
+
+	loc, err := time.LoadLocation("UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := hash(&TestConfig{Port: 8080, Hosts: []string{"a", "b"}, Location: loc, Password: "hunter2"}); c != a {
+		t.Fatalf("expected pointers to be hashed by value, received: %s, %s", c, a)
+	}
+
+	if c := hash(&TestConfig{Port: 8080, Hosts: []string{"a", "b"}, Location: time.UTC, Password: "rotated"}); c == a {
+		t.Fatal("expected secret rotation to change the hash")
+	}
+
+	if c := hash(&TestConfig{Port: 8080, Hosts: []string{"a b"}, Location: time.UTC, Password: "hunter2"}); c == a {
+		t.Fatal("expected different values to change the hash")
+	}
+
+	berlin1, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = time.Now().In(berlin1).String()
+	berlin2, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := hash(&TestConfig{Port: 8080, Hosts: []string{"a", "b"}, Location: berlin1, Password: "hunter2"})
+	d := hash(&TestConfig{Port: 8080, Hosts: []string{"a", "b"}, Location: berlin2, Password: "hunter2"})
+	if c != d {
+		t.Fatalf("expected equal hashes for separately loaded zones, received: %s, %s", c, d)
+	}
+	if c == a {
+		t.Fatal("expected a different zone to change the hash")
+	}
+
+	type PatternConfig struct {
+		Pattern *regexp.Regexp `config:"PATTERN"`
+	}
+
+	p1 := regexp.MustCompile("^a+b$")
+	p1.MatchString("aaab")
+	p2 := regexp.MustCompile("^a+b$")
+	h1, err := sc.ConfigHash(&PatternConfig{Pattern: p1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h2, err := sc.ConfigHash(&PatternConfig{Pattern: p2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h1 != h2 {
+		t.Fatalf("expected equal hashes for equal patterns, received: %s, %s", h1, h2)
+	}
+	h3, err := sc.ConfigHash(&PatternConfig{Pattern: regexp.MustCompile("^a*b$")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h3 == h1 {
+		t.Fatal("expected a different pattern to change the hash")
+	}
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HTTPStatusError is returned by an HTTP source when the endpoint answers with a status code outside of 2xx.
+type HTTPStatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("%s answered with status %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// An HTTPOption configures a source created with NewHTTPSource.
+type HTTPOption func(s *httpSource)
+
+// WithHTTPHeader adds a header to every request, e.g. WithHTTPHeader("Authorization", "Bearer "+token).
+func WithHTTPHeader(key, value string) HTTPOption {
+	return func(s *httpSource) {
+		s.header.Add(key, value)
+	}
+}
+
+// WithHTTPTimeout limits the time taken by every request, including reading the response. The default is 10
+// seconds.
+func WithHTTPTimeout(timeout time.Duration) HTTPOption {
+	return func(s *httpSource) {
+		s.timeout = timeout
+	}
+}
+
+// WithHTTPRefresh makes the source fetch the document again on the first lookup after ttl has passed since the last
+// fetch. By default, the document is only fetched once.
+//
+// When a refresh fails, lookups keep being answered from the last document fetched, and the next refresh is only
+// attempted once ttl has passed again, so that an unavailable endpoint does not slow down every lookup. Failed
+// refreshes are reported to the function given with WithHTTPRefreshError.
+func WithHTTPRefresh(ttl time.Duration) HTTPOption {
+	return func(s *httpSource) {
+		s.ttl = ttl
+	}
+}
+
+// WithHTTPRefreshError calls fn with the error of every failed refresh, see WithHTTPRefresh. fn is called without
+// holding the lock of the source, so it may look keys up.
+func WithHTTPRefreshError(fn func(err error)) HTTPOption {
+	return func(s *httpSource) {
+		s.onRefreshError = fn
+	}
+}
+
+// WithHTTPArraySeparator sets the separator used to join arrays when flattening the document, see Flatten. The
+// default is a space.
+func WithHTTPArraySeparator(sep string) HTTPOption {
+	return func(s *httpSource) {
+		s.arraySeparator = sep
+	}
+}
+
+// WithHTTPClient sets the client used for requests, e.g. to configure TLS. The default is http.DefaultClient.
+func WithHTTPClient(client *http.Client) HTTPOption {
+	return func(s *httpSource) {
+		s.client = client
+	}
+}
+
+type httpSource struct {
+	url            string
+	header         http.Header
+	timeout        time.Duration
+	ttl            time.Duration
+	arraySeparator string
+	client         *http.Client
+	onRefreshError func(err error)
+
+	mu      sync.Mutex
+	values  map[string]string
+	fetched time.Time
+}
+
+// NewHTTPSource fetches the JSON document served at url and returns it as a Source, flattened using Flatten. The
+// document must be an object. The document is fetched before NewHTTPSource returns, and again when it expires if
+// WithHTTPRefresh is given.
+//
+// When the first fetch fails, NewHTTPSource returns the error: a failed request, including a timeout, as an error,
+// and a response with a status code outside of 2xx as an *HTTPStatusError, so that they are never mistaken for a
+// missing key. Failed refreshes are handled as described in WithHTTPRefresh.
+func NewHTTPSource(url string, opts ...HTTPOption) (Source, error) {
+	s := &httpSource{
+		url:            url,
+		header:         make(http.Header),
+		timeout:        10 * time.Second,
+		arraySeparator: " ",
+		client:         http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	err := s.fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *httpSource) Lookup(key string) (string, bool, error) {
+	return s.LookupContext(context.Background(), key)
+}
+
+// LookupContext is like Lookup, but a refresh of the document is cancelled when ctx is done. A cancelled refresh is
+// attempted again on the next lookup.
+func (s *httpSource) LookupContext(ctx context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	var err error
+	if s.ttl > 0 && time.Since(s.fetched) >= s.ttl {
+		err = s.fetchLocked(ctx)
+		if err != nil && ctx.Err() == nil {
+			s.fetched = time.Now()
+		}
+	}
+	value, found := s.values[key]
+	s.mu.Unlock()
+
+	if err != nil && s.onRefreshError != nil {
+		s.onRefreshError(err)
+	}
+
+	return value, found, nil
+}
+
+func (s *httpSource) String() string {
+	return "http:" + s.url
+}
+
+func (s *httpSource) fetch() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.fetchLocked(context.Background())
+}
+
+func (s *httpSource) fetchLocked(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header = s.header.Clone()
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot fetch %s: %w", s.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &HTTPStatusError{URL: s.url, StatusCode: resp.StatusCode}
+	}
+
+	doc := make(map[string]interface{})
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	err = decoder.Decode(&doc)
+	if err != nil {
+		return fmt.Errorf("cannot decode %s: %w", s.url, err)
+	}
+
+	s.values = Flatten(doc, s.arraySeparator)
+	s.fetched = time.Now()
+	return nil
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPSource(t *testing.T) {
+	var port atomic.Int64
+	port.Store(8080)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"http": {"port": ` + strconv.FormatInt(port.Load(), 10) + `, "tags": ["a", "b"]}}`))
+	}))
+	defer server.Close()
+
+	src, err := NewHTTPSource(server.URL, WithHTTPHeader("Authorization", "Bearer token"), WithHTTPRefresh(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "HTTP",
+		ArraySeparator: " ",
+		Sources:        []Source{src},
+	}
+
+	type TestConfig struct {
+		Port int      `config:"PORT"`
+		Tags []string `config:"TAGS"`
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{Port: 8080, Tags: []string{"a", "b"}}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+
+	port.Store(9090)
+	time.Sleep(60 * time.Millisecond)
+
+	p, err := sc.GetInt("PORT")
+	if err != nil || p != 9090 {
+		t.Fatalf("expected refreshed value, received: %v, %v", p, err)
+	}
+
+	_, err = sc.GetString("MISSING")
+	if !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("expected ErrConfigNotFound, received: %v", err)
+	}
+}
+
+func TestNewHTTPSource_errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPSource(server.URL)
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected HTTPStatusError, received: %v", err)
+	}
+
+	_, err = NewHTTPSource(server.URL+"/slow", WithHTTPTimeout(50*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected timeout error, received: %v", err)
+	}
+}
+
+func TestNewHTTPSource_refreshError(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/vnd.config+json" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		if requests.Add(1) > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"port": 8080}`))
+	}))
+	defer server.Close()
+
+	var refreshErr error
+	src, err := NewHTTPSource(server.URL,
+		WithHTTPHeader("Accept", "application/vnd.config+json"),
+		WithHTTPRefresh(50*time.Millisecond),
+		WithHTTPRefreshError(func(err error) { refreshErr = err }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		value, found, err := src.Lookup("PORT")
+		if err != nil || !found || value != "8080" {
+			t.Fatalf("expected the last document to be served, received: %q, %v, %v", value, found, err)
+		}
+	}
+
+	var statusErr *HTTPStatusError
+	if !errors.As(refreshErr, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected the refresh error to be reported, received: %v", refreshErr)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Fatalf("expected a single refresh attempt until the ttl passed again, received %d requests", n)
+	}
+}
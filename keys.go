@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MatchingKeys returns the names of all environment variables that start with the Prefix, sorted alphabetically.
+func (sc ServiceConfig) MatchingKeys() []string {
+	prefix := sc.getConfigName("")
+
+	keys := make([]string, 0)
+	for _, env := range os.Environ() {
+		key, _, _ := strings.Cut(env, "=")
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// PlannedKeys returns the names of all environment variables that ParseTo would read for the struct pointed by obj,
+// in field declaration order. When Environment is set, the overlay name of each field is listed before its base name.
+func (sc ServiceConfig) PlannedKeys(obj interface{}) []string {
+	assertPointer(obj)
+	sc = sc.withStructSettings(obj)
+
+	keys := make([]string, 0)
+	for _, f := range configFields(obj) {
+		if f.name == "" || f.name == "-" {
+			continue
+		}
+
+		if sc.Environment != "" {
+			keys = append(keys, sc.getConfigName(sc.environmentName(f.name)))
+		}
+		keys = append(keys, sc.getConfigName(f.name))
+	}
+
+	return keys
+}
+
+// ExtraKeysError is returned by ParseTo when NoExtraKeys is set and the environment holds configs that no field reads.
+type ExtraKeysError struct {
+	// The full names of the extra configs, including the prefix, sorted alphabetically.
+	Keys []string
+}
+
+func (e *ExtraKeysError) Error() string {
+	return "unexpected configs, not read by any field: " + strings.Join(e.Keys, ", ")
+}
+
+// UnusedKeys returns the names of the environment variables that start with the Prefix but are not read by any
+// field of the struct pointed by obj, sorted alphabetically. It is useful to catch typos and stale configurations,
+// e.g. MYAPP_PROT set while the struct expects MYAPP_PORT. The chunks of fields tagged with the `chunked` option,
+// e.g. MYAPP_CERT_1, are read by their field. The environment is only read, never modified.
+func (sc ServiceConfig) UnusedKeys(obj interface{}) []string {
+	assertPointer(obj)
+	sc = sc.withStructSettings(obj)
+
+	planned := make(map[string]bool)
+	for _, key := range sc.PlannedKeys(obj) {
+		planned[key] = true
+	}
+
+	chunked := make([]string, 0)
+	for _, f := range configFields(obj) {
+		if f.opts.has("chunked") {
+			if sc.Environment != "" {
+				chunked = append(chunked, sc.getConfigName(sc.environmentName(f.name))+sc.keySeparator())
+			}
+			chunked = append(chunked, sc.getConfigName(f.name)+sc.keySeparator())
+		}
+	}
+
+	unused := make([]string, 0)
+	for _, key := range sc.MatchingKeys() {
+		if !planned[key] && !isChunkKey(key, chunked) {
+			unused = append(unused, key)
+		}
+	}
+
+	return unused
+}
+
+// isChunkKey reports whether key is a chunk of a `chunked` field, i.e. one of prefixes followed by an index.
+func isChunkKey(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		idx, ok := strings.CutPrefix(key, prefix)
+		if !ok || idx == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(idx); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetStringIndexed returns the config value of name for the index idx, e.g. of a shard or replica. The index
+// replaces the {n} placeholder in name, or is appended to name with the KeySeparator when name has no placeholder. For example,
+// with Prefix "MYAPP", both GetStringIndexed("SHARD_{n}_DSN", 2) and GetStringIndexed("SHARD_DSN", 2) compose their
+// key from the index, reading MYAPP_SHARD_2_DSN and MYAPP_SHARD_DSN_2 respectively.
+func (sc ServiceConfig) GetStringIndexed(name string, idx int, opts ...GetOption) (string, error) {
+	return sc.GetString(indexedName(name, idx, sc.keySeparator()), opts...)
+}
+
+// indexedName returns name with the index idx inserted, see GetStringIndexed.
+func indexedName(name string, idx int, sep string) string {
+	n := strconv.Itoa(idx)
+	if strings.Contains(name, "{n}") {
+		return strings.ReplaceAll(name, "{n}", n)
+	}
+
+	return name + sep + n
+}
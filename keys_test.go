@@ -0,0 +1,195 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServiceConfig_UnusedKeys(t *testing.T) {
+	type TestConfig struct {
+		Port int    `config:"PORT"`
+		Host string `config:"HOST"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "UNUSED",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("UNUSED_PROT", "80")
+	t.Setenv("UNUSED_HOST", "localhost")
+	t.Setenv("UNUSEDX_PORT", "80")
+
+	unused := sc.UnusedKeys(&TestConfig{})
+	expect := []string{"UNUSED_PROT"}
+	if !reflect.DeepEqual(expect, unused) {
+		t.Fatalf("unused keys are not the same with expectation, received: %v, expected: %v", unused, expect)
+	}
+
+	planned := sc.PlannedKeys(&TestConfig{})
+	expect = []string{"UNUSED_PORT", "UNUSED_HOST"}
+	if !reflect.DeepEqual(expect, planned) {
+		t.Fatalf("planned keys are not the same with expectation, received: %v, expected: %v", planned, expect)
+	}
+}
+
+func TestServiceConfig_GetStringIndexed(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "INDEXED",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("INDEXED_SHARD_COUNT", "2")
+	t.Setenv("INDEXED_SHARD_0_DSN", "postgres://a")
+	t.Setenv("INDEXED_SHARD_1_DSN", "postgres://b")
+	t.Setenv("INDEXED_REPLICA_1", "10.0.0.1")
+
+	count, err := sc.GetInt("SHARD_COUNT")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dsns := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		dsn, err := sc.GetStringIndexed("SHARD_{n}_DSN", i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dsns = append(dsns, dsn)
+	}
+
+	expected := []string{"postgres://a", "postgres://b"}
+	if !reflect.DeepEqual(dsns, expected) {
+		t.Fatalf("unexpected values, received: %v, expected: %v", dsns, expected)
+	}
+
+	replica, err := sc.GetStringIndexed("REPLICA", 1)
+	if err != nil || replica != "10.0.0.1" {
+		t.Fatalf("expected suffixed index, received: %v, %v", replica, err)
+	}
+
+	_, err = sc.GetStringIndexed("REPLICA", 2)
+	if !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("expected ErrConfigNotFound, received: %v", err)
+	}
+}
+
+func TestServiceConfig_KeySeparator(t *testing.T) {
+	t.Setenv("KEYSEP-PORT", "8080")
+	t.Setenv("KEYSEP-STAGING-PORT", "9090")
+	t.Setenv("KEYSEP-MAX-CONNS", "16")
+	t.Setenv("KEYSEP-HOSTS", "a,b")
+	t.Setenv("KEYSEP-SHARD-2", "db2")
+	t.Setenv("KEYSEP_PORT", "1")
+
+	sc := ServiceConfig{Prefix: "KEYSEP", ArraySeparator: ","}.WithKeySeparator("-")
+
+	type TestConfig struct {
+		Port     int      `config:"PORT"`
+		MaxConns int      `config:"MAX-CONNS"`
+		Hosts    []string `config:"HOSTS"`
+	}
+
+	var c TestConfig
+	err := sc.ParseTo(&c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := TestConfig{Port: 8080, MaxConns: 16, Hosts: []string{"a", "b"}}
+	if !reflect.DeepEqual(c, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", c, expected)
+	}
+
+	sc.Environment = "staging"
+	port, err := sc.GetInt("PORT")
+	if err != nil || port != 9090 {
+		t.Fatalf("expected the environment overlay 9090, received: %d, %v", port, err)
+	}
+
+	shard, err := sc.GetStringIndexed("SHARD", 2)
+	if err != nil || shard != "db2" {
+		t.Fatalf("expected db2, received: %s, %v", shard, err)
+	}
+
+	keys := sc.PlannedKeys(&c)
+	if keys[0] != "KEYSEP-STAGING-PORT" || keys[1] != "KEYSEP-PORT" {
+		t.Fatalf("unexpected planned keys: %v", keys)
+	}
+
+	port, err = ServiceConfig{Prefix: "KEYSEP"}.GetInt("PORT")
+	if err != nil || port != 1 {
+		t.Fatalf("expected the underscore key by default, received: %d, %v", port, err)
+	}
+
+	t.Setenv("KEYSEP-BAD", "x")
+	type BadConfig struct {
+		Bad int `config:"BAD"`
+	}
+	sc.Environment = ""
+	err = sc.ParseTo(&BadConfig{})
+	if err == nil || !strings.Contains(err.Error(), "cannot parse KEYSEP-BAD:") {
+		t.Fatalf("expected error naming the dashed key, received: %v", err)
+	}
+}
+
+func TestServiceConfig_NoExtraKeys(t *testing.T) {
+	type TestConfig struct {
+		Port int    `config:"PORT"`
+		Cert string `config:"CERT,chunked"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "NOEXTRA",
+		ArraySeparator: " ",
+		NoExtraKeys:    true,
+	}
+
+	t.Setenv("NOEXTRA_PORT", "80")
+	t.Setenv("NOEXTRA_CERT_1", "abc")
+	t.Setenv("NOEXTRA_CERT_2", "def")
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := &TestConfig{Port: 80, Cert: "abcdef"}
+	if !reflect.DeepEqual(expect, n) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expect)
+	}
+
+	t.Setenv("NOEXTRA_PROT", "80")
+	t.Setenv("NOEXTRA_CERT_X", "ghi")
+	err = sc.ParseTo(&TestConfig{})
+	var extraErr *ExtraKeysError
+	if !errors.As(err, &extraErr) {
+		t.Fatalf("expected ExtraKeysError, received: %v", err)
+	}
+
+	expectKeys := []string{"NOEXTRA_CERT_X", "NOEXTRA_PROT"}
+	if !reflect.DeepEqual(expectKeys, extraErr.Keys) {
+		t.Fatalf("extra keys are not the same with expectation, received: %v, expected: %v", extraErr.Keys, expectKeys)
+	}
+
+	sc.NoExtraKeys = false
+	err = sc.ParseTo(&TestConfig{})
+	if err != nil {
+		t.Fatalf("expected extra keys to be ignored without NoExtraKeys, received: %v", err)
+	}
+
+	sc.NoExtraKeys = true
+	lookup := MapSource{"NOEXTRA_PORT": "80"}
+	for _, custom := range []ServiceConfig{
+		{Prefix: sc.Prefix, NoExtraKeys: true, LookupFunc: func(key string) (string, bool) { return lookup[key], key == "NOEXTRA_PORT" }},
+		{Prefix: sc.Prefix, NoExtraKeys: true, Sources: []Source{lookup}},
+	} {
+		err = custom.ParseTo(&TestConfig{})
+		if err == nil || !strings.Contains(err.Error(), "NoExtraKeys") || errors.As(err, &extraErr) {
+			t.Fatalf("expected NoExtraKeys to be rejected with custom lookups, received: %v", err)
+		}
+	}
+}
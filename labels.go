@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InfoLabels returns the values of the fields of the struct pointed by obj whose config names are listed in include,
+// as labels for a Prometheus-style info metric, e.g. build_info{log_level="info",region="eu"} 1. When include is
+// empty, no label is returned. Label names are the config names in lower case, without the prefix, with every
+// character that Prometheus does not allow in label names replaced by "_", e.g. log_level for LOG-LEVEL.
+//
+// Fields tagged with the `secure` option, or whose config name matches SensitivePatterns, are never returned, even
+// when listed in include. obj is only read.
+func (sc ServiceConfig) InfoLabels(obj interface{}, include ...string) map[string]string {
+	assertPointer(obj)
+	sc = sc.withStructSettings(obj)
+
+	included := make(map[string]bool, len(include))
+	for _, name := range include {
+		included[name] = true
+	}
+
+	labels := make(map[string]string)
+	for _, f := range configFields(obj) {
+		if f.name == "" || f.opts.has("secure") || sc.isSensitive(f.key()) {
+			continue
+		}
+		if !included[f.key()] {
+			continue
+		}
+
+		labels[labelName(f.key())] = fmt.Sprintf("%v", f.value.Interface())
+	}
+
+	return labels
+}
+
+// labelName returns key in lower case as a valid Prometheus label name, matching [a-zA-Z_][a-zA-Z0-9_]*.
+func labelName(key string) string {
+	name := []byte(strings.ToLower(key))
+	for i, c := range name {
+		if !(c >= 'a' && c <= 'z' || c == '_' || i > 0 && c >= '0' && c <= '9') {
+			name[i] = '_'
+		}
+	}
+
+	return string(name)
+}
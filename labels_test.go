@@ -0,0 +1,55 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestServiceConfig_InfoLabels(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "LABELS",
+		ArraySeparator: " ",
+	}
+
+	type TestConfig struct {
+		Region   string `config:"REGION"`
+		LogLevel string `config:"LOG_LEVEL"`
+		Port     int    `config:"PORT"`
+		Password string `config:"PASSWORD,secure"`
+	}
+
+	n := &TestConfig{Region: "eu", LogLevel: "info", Port: 8080, Password: "hunter2"}
+
+	labels := sc.InfoLabels(n, "REGION", "LOG_LEVEL", "PASSWORD")
+	expected := map[string]string{"region": "eu", "log_level": "info"}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Fatalf("labels are not the same with expectation, received: %v, expected: %v", labels, expected)
+	}
+
+	labels = sc.InfoLabels(n)
+	if len(labels) != 0 {
+		t.Fatalf("expected no label without include, received: %v", labels)
+	}
+}
+
+func TestServiceConfig_InfoLabels_names(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:            "LABELS",
+		KeySeparator:      "-",
+		SensitivePatterns: DefaultSensitivePatterns,
+	}
+
+	type TestConfig struct {
+		LogLevel string `config:"LOG-LEVEL"`
+		Zone     string `config:"2ND.ZONE"`
+		APIToken string `config:"API-TOKEN"`
+	}
+
+	n := &TestConfig{LogLevel: "info", Zone: "b", APIToken: "hunter2"}
+
+	labels := sc.InfoLabels(n, "LOG-LEVEL", "2ND.ZONE", "API-TOKEN")
+	expected := map[string]string{"log_level": "info", "_nd_zone": "b"}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Fatalf("labels are not the same with expectation, received: %v, expected: %v", labels, expected)
+	}
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"net"
+)
+
+// GetIP returns the config value parsed as an IPv4 or IPv6 address with net.ParseIP.
+func (sc ServiceConfig) GetIP(name string, opts ...GetOption) (net.IP, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+
+	return parseIP(configData)
+}
+
+// GetIPArray returns the config value split using ArraySeparator, with every element parsed as an IP address, e.g.
+// for allowlists such as "10.0.0.1 10.0.0.2 ::1".
+func (sc ServiceConfig) GetIPArray(name string, opts ...GetOption) ([]net.IP, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+
+	return sc.parseIPArray(name, configData)
+}
+
+// GetIPArrayWithDefault returns the config value parsed like GetIPArray, defaultValue when the config does not exist,
+// or an empty slice when it is empty, see arrayWithDefault.
+func (sc ServiceConfig) GetIPArrayWithDefault(name string, defaultValue []net.IP, opts ...GetOption) ([]net.IP, error) {
+	sc = sc.withOptions(opts)
+	return arrayWithDefault(sc, name, defaultValue, func(configData string) ([]net.IP, error) {
+		return sc.parseIPArray(name, configData)
+	})
+}
+
+func (sc ServiceConfig) parseIPArray(name string, configData string) ([]net.IP, error) {
+	configDataArray, err := sc.split(configData)
+	if err != nil {
+		return nil, err
+	}
+
+	ips := make([]net.IP, 0, len(configDataArray))
+	for i, v := range configDataArray {
+		ip, err := parseIP(v)
+		if err != nil {
+			return nil, fmt.Errorf("config name %s element %d cannot be parsed: %w", name, i, err)
+		}
+		ips = append(ips, ip)
+	}
+
+	return ips, nil
+}
+
+func parseIP(s string) (net.IP, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address `%s`", s)
+	}
+
+	return ip, nil
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServiceConfig_GetIPArray(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "IP",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("IP_ALLOWLIST", "10.0.0.1 ::1")
+	t.Setenv("IP_BIND", "127.0.0.1")
+
+	expect := []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("::1")}
+	allowlist, err := sc.GetIPArray("ALLOWLIST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expect, allowlist) {
+		t.Fatalf("parsed array is not the same with expectation, received: %v, expected: %v", allowlist, expect)
+	}
+
+	def := []net.IP{net.ParseIP("192.168.0.1")}
+	missing, err := sc.GetIPArrayWithDefault("MISSING", def)
+	if err != nil || !reflect.DeepEqual(def, missing) {
+		t.Fatalf("expected default array, received: %v, %v", missing, err)
+	}
+
+	type TestConfig struct {
+		Allowlist []net.IP `config:"ALLOWLIST"`
+		Bind      net.IP   `config:"BIND"`
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expect, n.Allowlist) || !n.Bind.Equal(net.ParseIP("127.0.0.1")) {
+		t.Fatalf("unexpected decoded config: %v", n)
+	}
+
+	t.Setenv("IP_ALLOWLIST", "10.0.0.1 10.0.0")
+	_, err = sc.GetIPArray("ALLOWLIST")
+	if err == nil || !strings.Contains(err.Error(), "ALLOWLIST element 1") {
+		t.Fatalf("expected error naming the key and element index, received: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseInt parses s as a decimal integer, after removing NumberGrouping.
+func (sc ServiceConfig) parseInt(s string) (int, error) {
+	s = sc.ungroup(s)
+	if sc.RejectLeadingZeros && hasLeadingZero(s) {
+		return 0, fmt.Errorf("integer %q has a leading zero, which is ambiguous between octal and decimal", s)
+	}
+
+	return strconv.Atoi(s)
+}
+
+// parseIntBase parses s like parseInt, or in the base given by the `base` option of opts, as the elements of []int
+// fields are, e.g. "ff" or "0xff" with base=16.
+func (sc ServiceConfig) parseIntBase(s string, opts tagOptions) (int, error) {
+	b, ok := opts.get("base")
+	if !ok {
+		return sc.parseInt(s)
+	}
+
+	base, err := strconv.Atoi(b)
+	if err != nil {
+		return 0, fmt.Errorf("invalid base option `%s`: %w", b, err)
+	}
+
+	n, err := strconv.ParseInt(trimBasePrefix(sc.ungroup(s), base), base, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
+
+// hasLeadingZero reports whether the integer s, with an optional sign, starts with a zero followed by other digits.
+func hasLeadingZero(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	return len(s) > 1 && s[0] == '0'
+}
+
+// parseFloat parses s as a float of bitSize bits, after removing NumberGrouping.
+func (sc ServiceConfig) parseFloat(s string, bitSize int) (float64, error) {
+	return strconv.ParseFloat(sc.ungroup(s), bitSize)
+}
+
+// ungroup removes NumberGrouping from s.
+func (sc ServiceConfig) ungroup(s string) string {
+	if sc.NumberGrouping == "" {
+		return s
+	}
+
+	return strings.ReplaceAll(s, sc.NumberGrouping, "")
+}
+
+// checkNumberGrouping returns an error when NumberGrouping would be ambiguous with ArraySeparator.
+func (sc ServiceConfig) checkNumberGrouping() error {
+	if sc.NumberGrouping != "" && sc.NumberGrouping == sc.ArraySeparator {
+		return fmt.Errorf("NumberGrouping %q cannot be the same as ArraySeparator", sc.NumberGrouping)
+	}
+
+	return nil
+}
+
+// GetIntWithRange is like GetInt, but returns an error naming the key and the bounds when the value is not within
+// [min, max]. It is the getter counterpart of the `min` and `max` tag options.
+func (sc ServiceConfig) GetIntWithRange(name string, min, max int, opts ...GetOption) (int, error) {
+	n, err := sc.GetInt(name, opts...)
+	if errors.Is(err, ErrConfigNotFound) {
+		return 0, err
+	}
+	if err != nil {
+		return 0, sc.reformatParseError(name, err)
+	}
+	if n < min || n > max {
+		return 0, sc.reformatParseError(name, fmt.Errorf("value %d is not within [%d, %d]", n, min, max))
+	}
+
+	return n, nil
+}
+
+// GetFloat64WithRange is like GetIntWithRange, for GetFloat64.
+func (sc ServiceConfig) GetFloat64WithRange(name string, min, max float64, opts ...GetOption) (float64, error) {
+	n, err := sc.GetFloat64(name, opts...)
+	if errors.Is(err, ErrConfigNotFound) {
+		return 0, err
+	}
+	if err != nil {
+		return 0, sc.reformatParseError(name, err)
+	}
+	if n < min || n > max {
+		return 0, sc.reformatParseError(name, fmt.Errorf("value %v is not within [%v, %v]", n, min, max))
+	}
+
+	return n, nil
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServiceConfig_NumberGrouping(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "GROUPING",
+		ArraySeparator: " ",
+		NumberGrouping: ",",
+	}
+
+	t.Setenv("GROUPING_GROUPED", "1,000,000")
+	t.Setenv("GROUPING_UNGROUPED", "1000000")
+	t.Setenv("GROUPING_FLOAT", "12,345.5")
+	t.Setenv("GROUPING_LIST", "1,000 20 3,000,000")
+
+	for _, name := range []string{"GROUPED", "UNGROUPED"} {
+		n, err := sc.GetInt(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 1000000 {
+			t.Fatalf("unexpected value of %s: %d", name, n)
+		}
+	}
+
+	f, err := sc.GetFloat64("FLOAT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f != 12345.5 {
+		t.Fatalf("unexpected float value: %f", f)
+	}
+
+	list, err := sc.GetIntArray("LIST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]int{1000, 20, 3000000}, list) {
+		t.Fatalf("unexpected array value: %v", list)
+	}
+
+	sc.ArraySeparator = ","
+	_, err = sc.GetIntArray("LIST")
+	if err == nil {
+		t.Fatal("expected error when NumberGrouping is the same as ArraySeparator")
+	}
+
+	sc.NumberGrouping = ""
+	_, err = sc.GetInt("GROUPED")
+	if err == nil {
+		t.Fatal("expected grouped value to be rejected without NumberGrouping")
+	}
+}
+
+func TestServiceConfig_RejectLeadingZeros(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "LEADINGZERO",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("LEADINGZERO_MODE", "0755")
+	t.Setenv("LEADINGZERO_ZERO", "0")
+	t.Setenv("LEADINGZERO_NEGATIVE", "-08")
+	t.Setenv("LEADINGZERO_LIST", "10 020")
+
+	mode, err := sc.GetInt("MODE")
+	if err != nil || mode != 755 {
+		t.Fatalf("expected lenient parsing by default, received: %v, %v", mode, err)
+	}
+
+	sc.RejectLeadingZeros = true
+
+	for _, name := range []string{"MODE", "NEGATIVE"} {
+		_, err = sc.GetInt(name)
+		if err == nil || !strings.Contains(err.Error(), "leading zero") {
+			t.Fatalf("expected leading zero error for %s, received: %v", name, err)
+		}
+	}
+
+	zero, err := sc.GetInt("ZERO")
+	if err != nil || zero != 0 {
+		t.Fatalf("expected a lone zero to be accepted, received: %v, %v", zero, err)
+	}
+
+	_, err = sc.GetIntArray("LIST")
+	if err == nil || !strings.Contains(err.Error(), "020") {
+		t.Fatalf("expected error for the element with a leading zero, received: %v", err)
+	}
+
+	type TestConfig struct {
+		Mode int `config:"MODE"`
+	}
+
+	err = sc.ParseTo(&TestConfig{})
+	if err == nil || !strings.Contains(err.Error(), "LEADINGZERO_MODE") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+}
+
+func TestServiceConfig_GetIntWithRange(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "RANGE",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("RANGE_WORKERS", "4")
+	t.Setenv("RANGE_RATIO", "0.25")
+
+	n, err := sc.GetIntWithRange("WORKERS", 1, 4)
+	if err != nil || n != 4 {
+		t.Fatalf("expected 4, received: %v, %v", n, err)
+	}
+
+	_, err = sc.GetIntWithRange("WORKERS", 1, 3)
+	if err == nil || !strings.Contains(err.Error(), "RANGE_WORKERS") || !strings.Contains(err.Error(), "[1, 3]") {
+		t.Fatalf("expected error naming the key and bounds, received: %v", err)
+	}
+
+	_, err = sc.GetIntWithRange("MISSING", 1, 3)
+	if !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("expected ErrConfigNotFound, received: %v", err)
+	}
+
+	f, err := sc.GetFloat64WithRange("RATIO", 0, 1)
+	if err != nil || f != 0.25 {
+		t.Fatalf("expected 0.25, received: %v, %v", f, err)
+	}
+
+	_, err = sc.GetFloat64WithRange("RATIO", 0.5, 1)
+	if err == nil || !strings.Contains(err.Error(), "RANGE_RATIO") || !strings.Contains(err.Error(), "[0.5, 1]") {
+		t.Fatalf("expected error naming the key and bounds, received: %v", err)
+	}
+
+	t.Setenv("RANGE_RATIO", "half")
+	_, err = sc.GetFloat64WithRange("RATIO", 0, 1)
+	if err == nil || !strings.Contains(err.Error(), "RANGE_RATIO") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"strings"
+)
+
+// A GetOption changes how a single getter call reads its config, without changing the ServiceConfig it is called on.
+// Every option mirrors a `config` tag option, so that getters and ParseTo can be tuned the same way.
+type GetOption func(sc *ServiceConfig)
+
+// WithSeparator splits array values using sep instead of ArraySeparator. It mirrors the `sep` tag option, e.g.
+// `config:"HOSTS,sep=;"`. Since tag options are separated by commas, the tag option cannot use a comma separator.
+func WithSeparator(sep string) GetOption {
+	return func(sc *ServiceConfig) {
+		sc.ArraySeparator = sep
+	}
+}
+
+// WithTrim removes leading and trailing white space from the value, and from every element of array values. It
+// mirrors the `trim` tag option.
+func WithTrim() GetOption {
+	return func(sc *ServiceConfig) {
+		sc.trim = true
+	}
+}
+
+// WithEmptyAsUnset treats a config that is set to an empty value, after trimming when WithTrim is also given, the
+// same as a config that is not set, so that WithDefault getters return their default for both. It mirrors the
+// `emptyunset` tag option, with which ParseTo leaves the field as it is, or applies its `default` option.
+func WithEmptyAsUnset() GetOption {
+	return func(sc *ServiceConfig) {
+		sc.emptyAsUnset = true
+	}
+}
+
+// WithDropEmpty removes empty elements from array values, after trimming when WithTrim is also given. By default,
+// empty elements are kept, so that positional values such as "a,,c" with ArraySeparator "," yield three elements.
+// Since a leading or trailing separator produces an empty element, "a,b," yields [a b ""] by default, and [a b] with
+// WithDropEmpty. It mirrors the `dropempty` tag option.
+func WithDropEmpty() GetOption {
+	return func(sc *ServiceConfig) {
+		sc.dropEmpty = true
+	}
+}
+
+// Clone returns a copy of sc that can be modified without affecting sc, including its Sources, AutoSeparators and
+// SensitivePatterns.
+func (sc ServiceConfig) Clone() ServiceConfig {
+	if sc.Sources != nil {
+		sc.Sources = append([]Source(nil), sc.Sources...)
+	}
+	if sc.AutoSeparators != nil {
+		sc.AutoSeparators = append([]string(nil), sc.AutoSeparators...)
+	}
+	if sc.SensitivePatterns != nil {
+		sc.SensitivePatterns = append([]string(nil), sc.SensitivePatterns...)
+	}
+
+	return sc
+}
+
+// WithPrefix returns a copy of sc with the Prefix set to prefix, e.g. to read the configs of another component.
+func (sc ServiceConfig) WithPrefix(prefix string) ServiceConfig {
+	sc = sc.Clone()
+	sc.Prefix = prefix
+	return sc
+}
+
+// WithSeparator returns a copy of sc with the ArraySeparator set to sep. To change the separator of a single getter
+// call, see the WithSeparator function instead.
+func (sc ServiceConfig) WithSeparator(sep string) ServiceConfig {
+	sc = sc.Clone()
+	sc.ArraySeparator = sep
+	return sc
+}
+
+// WithKeySeparator returns a copy of sc with the KeySeparator set to sep, e.g. "-" to read MYAPP-PORT.
+func (sc ServiceConfig) WithKeySeparator(sep string) ServiceConfig {
+	sc = sc.Clone()
+	sc.KeySeparator = sep
+	return sc
+}
+
+// WithNumericBool makes boolean getters accept any decimal integer, 0 being false and every other integer true, for
+// systems that write flags such as -1. It mirrors the `numeric_bool` tag option.
+func WithNumericBool() GetOption {
+	return func(sc *ServiceConfig) {
+		sc.numericBool = true
+	}
+}
+
+// WithAutoSeparator splits array values using the first of AutoSeparators found in the value, instead of
+// ArraySeparator, e.g. to accept both "a,b" and "a;b" from operators with different habits. A value containing none
+// of them is a single element. It mirrors the `autosep` tag option.
+func WithAutoSeparator() GetOption {
+	return func(sc *ServiceConfig) {
+		sc.autoSeparator = true
+	}
+}
+
+// withOptions returns a copy of sc with opts applied.
+func (sc ServiceConfig) withOptions(opts []GetOption) ServiceConfig {
+	for _, opt := range opts {
+		opt(&sc)
+	}
+
+	return sc
+}
+
+// getOptions returns the GetOption equivalents of the tag options.
+func (o tagOptions) getOptions() []GetOption {
+	opts := make([]GetOption, 0)
+	if sep, ok := o.get("sep"); ok {
+		opts = append(opts, WithSeparator(sep))
+	}
+	if o.has("trim") {
+		opts = append(opts, WithTrim())
+	}
+	if o.has("emptyunset") {
+		opts = append(opts, WithEmptyAsUnset())
+	}
+	if o.has("dropempty") {
+		opts = append(opts, WithDropEmpty())
+	}
+	if o.has("numeric_bool") {
+		opts = append(opts, WithNumericBool())
+	}
+	if o.has("autosep") {
+		opts = append(opts, WithAutoSeparator())
+	}
+
+	return opts
+}
+
+// split splits an array value into its elements using ArraySeparator, applying the per-call settings.
+func (sc ServiceConfig) split(configData string) ([]string, error) {
+	err := sc.checkArraySeparator()
+	if err != nil {
+		return nil, err
+	}
+
+	var configDataArray []string
+	if sep, ok := sc.arraySeparator(configData); ok {
+		configDataArray = strings.Split(configData, sep)
+	} else {
+		configDataArray = []string{configData}
+	}
+	if sc.trim {
+		for i, v := range configDataArray {
+			configDataArray[i] = strings.TrimSpace(v)
+		}
+	}
+	if sc.dropEmpty {
+		kept := configDataArray[:0]
+		for _, v := range configDataArray {
+			if v != "" {
+				kept = append(kept, v)
+			}
+		}
+		configDataArray = kept
+	}
+
+	return configDataArray, nil
+}
+
+// arraySeparator returns the separator to split configData with: the ArraySeparator, or, with the autoSeparator
+// setting, the first of AutoSeparators found in configData. It reports false when no separator applies, in which case
+// configData is a single element.
+func (sc ServiceConfig) arraySeparator(configData string) (string, bool) {
+	if !sc.autoSeparator {
+		return sc.ArraySeparator, true
+	}
+
+	seps := sc.AutoSeparators
+	if len(seps) == 0 {
+		seps = defaultAutoSeparators
+	}
+	for _, sep := range seps {
+		if sep != "" && strings.Contains(configData, sep) {
+			return sep, true
+		}
+	}
+
+	return "", false
+}
+
+var defaultAutoSeparators = []string{",", ";", " "}
+
+// checkArraySeparator returns ErrNoArraySeparator when RequireArraySeparator is set and ArraySeparator is empty,
+// unless the separator is inferred from the value.
+func (sc ServiceConfig) checkArraySeparator() error {
+	if sc.RequireArraySeparator && sc.ArraySeparator == "" && !sc.autoSeparator {
+		return ErrNoArraySeparator
+	}
+
+	return nil
+}
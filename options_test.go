@@ -0,0 +1,321 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestServiceConfig_GetOption(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "OPTIONS",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("OPTIONS_HOSTS", " a, b ,c ")
+	t.Setenv("OPTIONS_PORT", " 80 ")
+
+	hosts, err := sc.GetStringArray("HOSTS", WithSeparator(","), WithTrim())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]string{"a", "b", "c"}, hosts) {
+		t.Fatalf("unexpected array value: %q", hosts)
+	}
+
+	port, err := sc.GetInt("PORT", WithTrim())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port != 80 {
+		t.Fatalf("unexpected int value: %d", port)
+	}
+
+	_, err = sc.GetInt("PORT")
+	if err == nil {
+		t.Fatal("expected options not to persist across calls")
+	}
+
+	type TestConfig struct {
+		Hosts []string `config:"HOSTS,sep=;,trim"`
+		Port  int      `config:"PORT,trim"`
+	}
+
+	t.Setenv("OPTIONS_HOSTS", " a; b ;c ")
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := &TestConfig{Hosts: []string{"a", "b", "c"}, Port: 80}
+	if !reflect.DeepEqual(expect, n) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expect)
+	}
+}
+
+func TestServiceConfig_RequireArraySeparator(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:                "NOSEP",
+		RequireArraySeparator: true,
+	}
+
+	t.Setenv("NOSEP_HOSTS", "a b")
+	t.Setenv("NOSEP_HOST", "a")
+
+	_, err := sc.GetStringArray("HOSTS")
+	if !errors.Is(err, ErrNoArraySeparator) {
+		t.Fatalf("expected ErrNoArraySeparator, received: %v", err)
+	}
+
+	_, err = sc.GetIntArray("HOSTS")
+	if !errors.Is(err, ErrNoArraySeparator) {
+		t.Fatalf("expected ErrNoArraySeparator, received: %v", err)
+	}
+
+	host, err := sc.GetString("HOST")
+	if err != nil || host != "a" {
+		t.Fatalf("expected non-array getters to be unaffected, received: %v, %v", host, err)
+	}
+
+	hosts, err := sc.GetStringArray("HOSTS", WithSeparator(" "))
+	if err != nil || !reflect.DeepEqual([]string{"a", "b"}, hosts) {
+		t.Fatalf("expected per-call separator to satisfy the requirement, received: %v, %v", hosts, err)
+	}
+}
+
+func TestServiceConfig_GetStringArrayWithDefault_emptyAsUnset(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "EMPTYUNSET",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("EMPTYUNSET_EMPTY", "")
+	t.Setenv("EMPTYUNSET_BLANK", "  ")
+	t.Setenv("EMPTYUNSET_HOSTS", "a b")
+
+	defaultValue := []string{"localhost"}
+	cases := []struct {
+		name     string
+		opts     []GetOption
+		expected []string
+	}{
+		{"UNSET", nil, defaultValue},
+		{"UNSET", []GetOption{WithEmptyAsUnset()}, defaultValue},
+		{"EMPTY", nil, []string{}},
+		{"EMPTY", []GetOption{WithEmptyAsUnset()}, defaultValue},
+		{"BLANK", []GetOption{WithEmptyAsUnset()}, []string{"", "", ""}},
+		{"BLANK", []GetOption{WithEmptyAsUnset(), WithTrim()}, defaultValue},
+		{"HOSTS", []GetOption{WithEmptyAsUnset()}, []string{"a", "b"}},
+	}
+
+	for _, c := range cases {
+		v, err := sc.GetStringArrayWithDefault(c.name, defaultValue, c.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(v, c.expected) {
+			t.Fatalf("unexpected value for %s with %d options, received: %q, expected: %q", c.name, len(c.opts), v, c.expected)
+		}
+	}
+
+	type TestConfig struct {
+		Hosts []string `config:"EMPTY,emptyunset,default=localhost"`
+	}
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(n.Hosts, defaultValue) {
+		t.Fatalf("expected default for empty config, received: %q", n.Hosts)
+	}
+}
+
+func TestServiceConfig_WithDropEmpty(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "DROPEMPTY",
+		ArraySeparator: ",",
+	}
+
+	t.Setenv("DROPEMPTY_COLUMNS", "a,,c,")
+	t.Setenv("DROPEMPTY_PORTS", "80,,443")
+
+	v, err := sc.GetStringArray("COLUMNS")
+	if err != nil || !reflect.DeepEqual(v, []string{"a", "", "c", ""}) {
+		t.Fatalf("expected empty elements to be kept by default, received: %q, %v", v, err)
+	}
+
+	v, err = sc.GetStringArray("COLUMNS", WithDropEmpty())
+	if err != nil || !reflect.DeepEqual(v, []string{"a", "c"}) {
+		t.Fatalf("expected empty elements to be dropped, received: %q, %v", v, err)
+	}
+
+	ranged := make([]string, 0)
+	err = sc.RangeStringArray("COLUMNS", func(s string) bool {
+		ranged = append(ranged, s)
+		return true
+	}, WithDropEmpty())
+	if err != nil || !reflect.DeepEqual(ranged, []string{"a", "c"}) {
+		t.Fatalf("expected empty elements to be skipped, received: %q, %v", ranged, err)
+	}
+
+	type TestConfig struct {
+		Columns []string `config:"COLUMNS,dropempty"`
+		Ports   []int    `config:"PORTS,dropempty"`
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{Columns: []string{"a", "c"}, Ports: []int{80, 443}}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+}
+
+func TestServiceConfig_Clone(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:            "CLONE",
+		ArraySeparator:    " ",
+		RequireAll:        true,
+		Sources:           []Source{MapSource{"CLONE_HOSTS": "a b", "OTHER_HOSTS": "c,d"}},
+		AutoSeparators:    []string{",", " "},
+		SensitivePatterns: []string{"PASSWORD"},
+	}
+
+	clone := sc.Clone()
+	clone.Sources[0] = MapSource{}
+	clone.Sources = append(clone.Sources, EnvSource{})
+	if len(sc.Sources) != 1 || reflect.DeepEqual(sc.Sources[0], MapSource{}) {
+		t.Fatalf("expected the original to be unmodified, received: %v", sc.Sources)
+	}
+
+	clone.AutoSeparators[0] = ";"
+	clone.SensitivePatterns[0] = "TOKEN"
+	if !reflect.DeepEqual(sc.AutoSeparators, []string{",", " "}) || !reflect.DeepEqual(sc.SensitivePatterns, []string{"PASSWORD"}) {
+		t.Fatalf("expected the original to be unmodified, received: %v, %v", sc.AutoSeparators, sc.SensitivePatterns)
+	}
+
+	other := sc.WithPrefix("OTHER").WithSeparator(",")
+	if sc.Prefix != "CLONE" || sc.ArraySeparator != " " || !other.RequireAll {
+		t.Fatalf("unexpected configs: %+v, %+v", sc, other)
+	}
+
+	hosts, err := other.GetStringArray("HOSTS")
+	if err != nil || !reflect.DeepEqual(hosts, []string{"c", "d"}) {
+		t.Fatalf("unexpected hosts: %v, %v", hosts, err)
+	}
+
+	hosts, err = sc.GetStringArray("HOSTS")
+	if err != nil || !reflect.DeepEqual(hosts, []string{"a", "b"}) {
+		t.Fatalf("unexpected hosts: %v, %v", hosts, err)
+	}
+}
+
+func TestServiceConfig_WithNumericBool(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "NUMERICBOOL",
+		ArraySeparator: " ",
+	}
+
+	cases := []struct {
+		value    string
+		expected bool
+		lenient  bool
+	}{
+		{"0", false, true},
+		{"1", true, true},
+		{"-1", true, false},
+		{"2", true, false},
+		{"00", false, false},
+		{"true", true, true},
+		{"F", false, true},
+	}
+
+	for _, c := range cases {
+		t.Setenv("NUMERICBOOL_FLAG", c.value)
+
+		v, err := sc.GetBool("FLAG", WithNumericBool())
+		if err != nil || v != c.expected {
+			t.Fatalf("unexpected result for %q, received: %v, %v", c.value, v, err)
+		}
+
+		_, err = sc.GetBool("FLAG")
+		if (err == nil) != c.lenient {
+			t.Fatalf("expected behavior without the option to be unchanged for %q, received: %v", c.value, err)
+		}
+	}
+
+	t.Setenv("NUMERICBOOL_FLAG", "yes")
+	_, err := sc.GetBool("FLAG", WithNumericBool())
+	if err == nil {
+		t.Fatal("expected error for a value that is neither an integer nor a boolean")
+	}
+
+	type TestConfig struct {
+		Flag bool `config:"FLAG,numeric_bool"`
+	}
+
+	t.Setenv("NUMERICBOOL_FLAG", "-1")
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil || !n.Flag {
+		t.Fatalf("expected -1 to be true, received: %v, %v", n.Flag, err)
+	}
+}
+
+func TestServiceConfig_WithAutoSeparator(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "AUTOSEP",
+		ArraySeparator: " ",
+	}
+
+	cases := []struct {
+		value    string
+		expected []string
+	}{
+		{"a,b c", []string{"a", "b c"}},
+		{"a;b c", []string{"a", "b c"}},
+		{"a b", []string{"a", "b"}},
+		{"a", []string{"a"}},
+	}
+
+	for _, c := range cases {
+		t.Setenv("AUTOSEP_HOSTS", c.value)
+
+		v, err := sc.GetStringArray("HOSTS", WithAutoSeparator())
+		if err != nil || !reflect.DeepEqual(v, c.expected) {
+			t.Fatalf("unexpected result for %q, received: %q, %v", c.value, v, err)
+		}
+	}
+
+	t.Setenv("AUTOSEP_HOSTS", "a|b;c")
+	sc.AutoSeparators = []string{"|", ";"}
+	v, err := sc.GetStringArray("HOSTS", WithAutoSeparator())
+	if err != nil || !reflect.DeepEqual(v, []string{"a", "b;c"}) {
+		t.Fatalf("expected the configured priority to apply, received: %q, %v", v, err)
+	}
+
+	type TestConfig struct {
+		Ports []int    `config:"PORTS,autosep"`
+		Hosts []string `config:"HOSTS"`
+	}
+
+	t.Setenv("AUTOSEP_PORTS", "80;443")
+	sc.AutoSeparators = nil
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &TestConfig{Ports: []int{80, 443}, Hosts: []string{"a|b;c"}}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+}
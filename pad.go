@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// parsePad parses the `pad` option, N:default, into the target length and the default element.
+func parsePad(pad string) (int, string, error) {
+	length, def, ok := strings.Cut(pad, ":")
+	n, err := strconv.Atoi(length)
+	if !ok || err != nil || n < 0 {
+		return 0, "", fmt.Errorf("invalid pad `%s`, expected N:default", pad)
+	}
+
+	return n, def, nil
+}
+
+// padElement parses the default element of the `pad` option like an element of the slice held by field, with the
+// options of the field that apply to elements, such as `unit` or `layout`.
+func (sc ServiceConfig) padElement(field reflect.Value, tag string, pad string, opts tagOptions) (int, reflect.Value, error) {
+	if field.Kind() != reflect.Slice {
+		return 0, reflect.Value{}, fmt.Errorf("pad is only supported for slice fields, not %s", field.Type())
+	}
+
+	n, def, err := parsePad(pad)
+	if err != nil {
+		return 0, reflect.Value{}, err
+	}
+
+	elemOpts := make(tagOptions, len(opts))
+	for key, value := range opts {
+		switch key {
+		case "pad", "unique", "sorted", "json", "autosep":
+		default:
+			elemOpts[key] = value
+		}
+	}
+
+	elem := reflect.New(field.Type().Elem()).Elem()
+	err = sc.setField(elem, tag, def, elemOpts)
+	if err != nil {
+		return 0, reflect.Value{}, fmt.Errorf("invalid pad default `%s`: %w", def, err)
+	}
+
+	return n, elem, nil
+}
+
+// padField appends elem to the slice held by field until it has n elements. Longer slices are left as they are.
+func padField(field reflect.Value, n int, elem reflect.Value) {
+	for field.Len() < n {
+		field.Set(reflect.Append(field, elem))
+	}
+}
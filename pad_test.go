@@ -0,0 +1,84 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServiceConfig_ParseTo_pad(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "PAD",
+		ArraySeparator: " ",
+	}
+
+	type TestConfig struct {
+		Weights  []int           `config:"WEIGHTS,pad=3:1"`
+		Names    []string        `config:"NAMES,pad=2:default"`
+		Timeouts []time.Duration `config:"TIMEOUTS,unit=s,pad=3:30"`
+		Empty    []string        `config:"EMPTY,pad=2:x"`
+		Long     []int           `config:"LONG,pad=2:0"`
+		Masks    []int           `config:"MASKS,base=16,pad=3:0xFF"`
+	}
+
+	t.Setenv("PAD_WEIGHTS", "5")
+	t.Setenv("PAD_NAMES", "a b")
+	t.Setenv("PAD_TIMEOUTS", "1m")
+	t.Setenv("PAD_EMPTY", "")
+	t.Setenv("PAD_LONG", "1 2 3")
+	t.Setenv("PAD_MASKS", "a 10")
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := &TestConfig{
+		Weights:  []int{5, 1, 1},
+		Names:    []string{"a", "b"},
+		Timeouts: []time.Duration{time.Minute, 30 * time.Second, 30 * time.Second},
+		Empty:    []string{"x", "x"},
+		Long:     []int{1, 2, 3},
+		Masks:    []int{10, 16, 255},
+	}
+	if !reflect.DeepEqual(expect, n) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expect)
+	}
+
+	type MaxConfig struct {
+		Long []int `config:"LONG,pad=2:0,maxlen=2"`
+	}
+
+	err = sc.ParseTo(&MaxConfig{})
+	if err == nil || !strings.Contains(err.Error(), "PAD_LONG") || !strings.Contains(err.Error(), "at most 2") {
+		t.Fatalf("expected maxlen to reject the long list, received: %v", err)
+	}
+
+	type LenConfig struct {
+		Weights []int `config:"WEIGHTS,pad=3:1,len=3"`
+	}
+
+	err = sc.ParseTo(&LenConfig{})
+	if err != nil {
+		t.Fatalf("expected padding to satisfy len, received: %v", err)
+	}
+
+	type NoDefaultConfig struct {
+		Weights []int `config:"WEIGHTS,pad=3"`
+	}
+	type BadLengthConfig struct {
+		Weights []int `config:"WEIGHTS,pad=x:1"`
+	}
+	type BadDefaultConfig struct {
+		Weights []int `config:"WEIGHTS,pad=3:one"`
+	}
+
+	for _, obj := range []interface{}{&NoDefaultConfig{}, &BadLengthConfig{}, &BadDefaultConfig{}} {
+		err = sc.ParseTo(obj)
+		if err == nil || !strings.Contains(err.Error(), "PAD_WEIGHTS") {
+			t.Fatalf("%T: expected error naming the key, received: %v", obj, err)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	providersMu sync.RWMutex
+	providers   = make(map[string]func() (string, error))
+)
+
+// RegisterProvider registers fn under name, so that ParseTo can call it as a last resort to produce the value of a
+// field tagged with `provider=name`, e.g. `config:"INSTANCE_ID,provider=generateID"`. Registering an existing name
+// replaces its function.
+//
+// Unlike a function registered with RegisterDefaultFunc, which takes precedence over the `default` option, a provider
+// only runs when the field is neither configured nor has a default.
+func RegisterProvider(name string, fn func() (string, error)) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	providers[name] = fn
+}
+
+// callProvider returns the value produced by the provider registered under name.
+func callProvider(name string) (string, error) {
+	providersMu.RLock()
+	fn, ok := providers[name]
+	providersMu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("unknown provider %q, register it with RegisterProvider", name)
+	}
+
+	value, err := fn()
+	if err != nil {
+		return "", fmt.Errorf("provider %s: %w", name, err)
+	}
+	return value, nil
+}
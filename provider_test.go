@@ -0,0 +1,62 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServiceConfig_ParseTo_provider(t *testing.T) {
+	calls := 0
+	RegisterProvider("testInstanceID", func() (string, error) {
+		calls++
+		return "generated", nil
+	})
+	RegisterProvider("testFailing", func() (string, error) {
+		return "", errors.New("no entropy")
+	})
+
+	type TestConfig struct {
+		InstanceID string `config:"INSTANCE_ID,provider=testInstanceID,required"`
+		Region     string `config:"REGION,provider=testInstanceID"`
+		Zone       string `config:"ZONE,provider=testInstanceID,default=a"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "PROVIDER",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("PROVIDER_REGION", "eu")
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{InstanceID: "generated", Region: "eu", Zone: "a"}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+	if calls != 1 {
+		t.Fatalf("expected the provider to run only for the field without other values, received %d calls", calls)
+	}
+
+	type Failing struct {
+		ID string `config:"ID,provider=testFailing"`
+	}
+	err = sc.ParseTo(&Failing{})
+	if err == nil || !strings.Contains(err.Error(), "PROVIDER_ID") || !strings.Contains(err.Error(), "no entropy") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+
+	type Unknown struct {
+		ID string `config:"ID,provider=testUnknown"`
+	}
+	err = sc.ParseTo(&Unknown{})
+	if err == nil || !strings.Contains(err.Error(), "RegisterProvider") {
+		t.Fatalf("expected an unknown provider error, received: %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+// Package redissource provides a watchable config.Source reading from Redis. It is kept in its own module so that the
+// Redis dependency is only required by services that use it.
+package redissource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	config "github.com/potatobeansco/go-config"
+	"github.com/redis/go-redis/v9"
+)
+
+// Source is a config.WatchableSource reading from Redis. Values are cached after their first lookup, and changes
+// announced on a pub/sub channel invalidate the cache and notify the functions registered with OnChange.
+type Source struct {
+	client    redis.UniversalClient
+	keyPrefix string
+	hashKey   string
+	pubsub    *redis.PubSub
+	done      chan struct{}
+
+	mu    sync.RWMutex
+	cache map[string]entry
+	// generation is incremented by every invalidation, so that a value fetched while one happened, which may be
+	// stale, is not cached.
+	generation uint64
+	onChange   []func(key string)
+}
+
+type entry struct {
+	value string
+	found bool
+}
+
+// NewRedisSource returns a Source reading through client and subscribes to channel for changes.
+//
+// A config key is read from the string key keyPrefix+key, and when that does not exist, from the field named key of
+// the hash stored at keyPrefix with any trailing ":" removed. For example, with keyPrefix "config:", MYAPP_PORT is
+// read from the string key "config:MYAPP_PORT", or from the field MYAPP_PORT of the hash "config".
+//
+// Every message published on channel carries the config key that changed, or "*" when all keys may have changed.
+// When the subscription is lost, e.g. because Redis restarted, messages published until it is established again are
+// missed, so the whole cache is invalidated then, as with "*".
+//
+// A lookup that fails because of Redis is returned as an error, while a key that does not exist in Redis is reported
+// as not found.
+//
+// The Source must be closed with Close to unsubscribe.
+func NewRedisSource(client redis.UniversalClient, keyPrefix, channel string) (*Source, error) {
+	if client == nil {
+		return nil, errors.New("redissource: client is nil")
+	}
+
+	pubsub := client.Subscribe(context.Background(), channel)
+	_, err := pubsub.Receive(context.Background())
+	if err != nil {
+		_ = pubsub.Close()
+		return nil, fmt.Errorf("redissource: cannot subscribe to %s: %w", channel, err)
+	}
+
+	s := &Source{
+		client:    client,
+		keyPrefix: keyPrefix,
+		hashKey:   strings.TrimRight(keyPrefix, ":"),
+		pubsub:    pubsub,
+		done:      make(chan struct{}),
+		cache:     make(map[string]entry),
+	}
+
+	go s.listen()
+	return s, nil
+}
+
+func (s *Source) Lookup(key string) (string, bool, error) {
+	return s.LookupContext(context.Background(), key)
+}
+
+// LookupContext is like Lookup, but the commands sent to Redis are cancelled when ctx is done.
+func (s *Source) LookupContext(ctx context.Context, key string) (string, bool, error) {
+	s.mu.RLock()
+	e, ok := s.cache[key]
+	generation := s.generation
+	s.mu.RUnlock()
+	if ok {
+		return e.value, e.found, nil
+	}
+
+	e, err := s.fetch(ctx, key)
+	if err != nil {
+		return "", false, fmt.Errorf("redissource: cannot read %s: %w", key, err)
+	}
+
+	s.mu.Lock()
+	if s.generation == generation {
+		s.cache[key] = e
+	}
+	s.mu.Unlock()
+
+	return e.value, e.found, nil
+}
+
+func (s *Source) fetch(ctx context.Context, key string) (entry, error) {
+	value, err := s.client.Get(ctx, s.keyPrefix+key).Result()
+	if err == nil {
+		return entry{value: value, found: true}, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		return entry{}, err
+	}
+
+	value, err = s.client.HGet(ctx, s.hashKey, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return entry{}, nil
+	}
+	if err != nil {
+		return entry{}, err
+	}
+
+	return entry{value: value, found: true}, nil
+}
+
+func (s *Source) OnChange(fn func(key string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onChange = append(s.onChange, fn)
+}
+
+// Close unsubscribes from the channel. The client is not closed.
+func (s *Source) Close() error {
+	err := s.pubsub.Close()
+	<-s.done
+	return err
+}
+
+// String returns the name of the source, used when reporting where a config came from.
+func (s *Source) String() string {
+	return "redis:" + s.keyPrefix
+}
+
+func (s *Source) listen() {
+	defer close(s.done)
+
+	for msg := range s.pubsub.ChannelWithSubscriptions() {
+		switch msg := msg.(type) {
+		case *redis.Message:
+			s.invalidate(msg.Payload)
+		case *redis.Subscription:
+			// The subscription was established again after the connection was lost, so changes published meanwhile
+			// were missed, and every cached value may be stale.
+			if msg.Kind == "subscribe" {
+				s.invalidate("*")
+			}
+		}
+	}
+}
+
+// invalidate removes key from the cache, or every key when key is "*", and notifies the functions registered with
+// OnChange.
+func (s *Source) invalidate(key string) {
+	s.mu.Lock()
+	s.generation++
+	changed := []string{key}
+	if key == "*" {
+		changed = make([]string, 0, len(s.cache))
+		for key := range s.cache {
+			changed = append(changed, key)
+		}
+		s.cache = make(map[string]entry)
+	} else {
+		delete(s.cache, key)
+	}
+	callbacks := s.onChange
+	s.mu.Unlock()
+
+	for _, key := range changed {
+		for _, fn := range callbacks {
+			fn(key)
+		}
+	}
+}
+
+var (
+	_ config.WatchableSource = (*Source)(nil)
+	_ config.ContextSource   = (*Source)(nil)
+)
@@ -0,0 +1,206 @@
+package redissource
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestNewRedisSource(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	server.Set("config:MYAPP_PORT", "80")
+	server.HSet("config", "MYAPP_HOST", "localhost")
+
+	source, err := NewRedisSource(client, "config:", "config-changes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Close()
+
+	for key, expect := range map[string]string{"MYAPP_PORT": "80", "MYAPP_HOST": "localhost"} {
+		value, found, err := source.Lookup(key)
+		if err != nil || !found || value != expect {
+			t.Fatalf("unexpected lookup result for %s: %q, %v, %v", key, value, found, err)
+		}
+	}
+
+	_, found, err := source.Lookup("MYAPP_MISSING")
+	if err != nil || found {
+		t.Fatalf("expected missing key to be not found without error, received: %v, %v", found, err)
+	}
+
+	changes := make(chan string, 1)
+	source.OnChange(func(key string) {
+		changes <- key
+	})
+
+	server.Set("config:MYAPP_PORT", "443")
+	server.Publish("config-changes", "MYAPP_PORT")
+
+	select {
+	case key := <-changes:
+		if key != "MYAPP_PORT" {
+			t.Fatalf("unexpected changed key: %s", key)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("change was not notified")
+	}
+
+	value, _, _ := source.Lookup("MYAPP_PORT")
+	if value != "443" {
+		t.Fatalf("expected invalidated value 443, received: %s", value)
+	}
+
+	server.Close()
+	_, _, err = source.Lookup("MYAPP_OTHER")
+	if err == nil {
+		t.Fatal("expected error when redis is unavailable")
+	}
+}
+
+func TestSource_LookupContext(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	server.Set("config:MYAPP_PORT", "80")
+
+	source, err := NewRedisSource(client, "config:", "config-changes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = source.LookupContext(ctx, "MYAPP_PORT")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a cancellation error, received: %v", err)
+	}
+
+	value, found, err := source.LookupContext(context.Background(), "MYAPP_PORT")
+	if err != nil || !found || value != "80" {
+		t.Fatalf("expected the value after a cancelled lookup, received: %q, %v, %v", value, found, err)
+	}
+}
+
+// invalidatingHook changes a key and announces the change right after the first GET of it was answered, as if the
+// change happened while a lookup was in flight.
+type invalidatingHook struct {
+	server *miniredis.Miniredis
+	source *Source
+	done   bool
+}
+
+func (h *invalidatingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *invalidatingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		if h.done || h.source == nil || cmd.Name() != "get" {
+			return err
+		}
+
+		h.done = true
+		h.source.mu.RLock()
+		generation := h.source.generation
+		h.source.mu.RUnlock()
+
+		h.server.Set("config:MYAPP_PORT", "443")
+		h.server.Publish("config-changes", "MYAPP_PORT")
+		for {
+			h.source.mu.RLock()
+			invalidated := h.source.generation != generation
+			h.source.mu.RUnlock()
+			if invalidated {
+				return err
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func (h *invalidatingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestSource_invalidationDuringLookup(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	server.Set("config:MYAPP_PORT", "80")
+
+	source, err := NewRedisSource(client, "config:", "config-changes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Close()
+
+	client.AddHook(&invalidatingHook{server: server, source: source})
+
+	value, _, err := source.Lookup("MYAPP_PORT")
+	if err != nil || value != "80" {
+		t.Fatalf("expected the value read before the change, received: %q, %v", value, err)
+	}
+
+	value, _, err = source.Lookup("MYAPP_PORT")
+	if err != nil || value != "443" {
+		t.Fatalf("expected the stale value not to be cached, received: %q, %v", value, err)
+	}
+}
+
+func TestSource_resubscribe(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	server.Set("config:MYAPP_PORT", "80")
+
+	source, err := NewRedisSource(client, "config:", "config-changes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Close()
+
+	changed := make(chan string, 1)
+	source.OnChange(func(key string) {
+		changed <- key
+	})
+
+	value, _, err := source.Lookup("MYAPP_PORT")
+	if err != nil || value != "80" {
+		t.Fatalf("unexpected value: %q, %v", value, err)
+	}
+
+	// The change is made while the source is disconnected, so its announcement is missed.
+	server.Close()
+	server.Set("config:MYAPP_PORT", "443")
+	err = server.Restart()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case key := <-changed:
+		if key != "MYAPP_PORT" {
+			t.Fatalf("unexpected changed key: %s", key)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the cache to be invalidated once subscribed again")
+	}
+
+	value, _, err = source.Lookup("MYAPP_PORT")
+	if err != nil || value != "443" {
+		t.Fatalf("expected the value changed while disconnected, received: %q, %v", value, err)
+	}
+}
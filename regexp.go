@@ -0,0 +1,33 @@
+package config
+
+import (
+	"errors"
+	"regexp"
+)
+
+// GetRegexp returns the config value compiled with regexp.Compile, so that a pattern is compiled once at load.
+func (sc ServiceConfig) GetRegexp(name string, opts ...GetOption) (*regexp.Regexp, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+
+	re, err := regexp.Compile(configData)
+	if err != nil {
+		return nil, sc.reformatParseError(name, err)
+	}
+	return re, nil
+}
+
+func (sc ServiceConfig) GetRegexpWithDefault(name string, defaultValue *regexp.Regexp, opts ...GetOption) (*regexp.Regexp, error) {
+	sc = sc.withOptions(opts)
+	re, err := sc.GetRegexp(name)
+	if errors.Is(err, ErrConfigNotFound) {
+		return defaultValue, nil
+	}
+	return re, err
+}
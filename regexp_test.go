@@ -0,0 +1,58 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestServiceConfig_GetRegexp(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "REGEXP",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("REGEXP_PATH", `^/api/v[0-9]+/`)
+	t.Setenv("REGEXP_BROKEN", `(unclosed`)
+
+	re, err := sc.GetRegexp("PATH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !re.MatchString("/api/v2/users") || re.MatchString("/web") {
+		t.Fatalf("unexpected pattern: %s", re)
+	}
+
+	_, err = sc.GetRegexp("BROKEN")
+	if err == nil || !strings.Contains(err.Error(), "REGEXP_BROKEN") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+
+	defaultValue := regexp.MustCompile(".*")
+	re, err = sc.GetRegexpWithDefault("MISSING", defaultValue)
+	if err != nil || re != defaultValue {
+		t.Fatalf("expected default pattern, received: %v, %v", re, err)
+	}
+
+	type TestConfig struct {
+		Path *regexp.Regexp `config:"PATH"`
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Path.String() != `^/api/v[0-9]+/` {
+		t.Fatalf("unexpected decoded pattern: %s", n.Path)
+	}
+
+	type BrokenConfig struct {
+		Path *regexp.Regexp `config:"BROKEN"`
+	}
+
+	err = sc.ParseTo(&BrokenConfig{})
+	if err == nil || !strings.Contains(err.Error(), "REGEXP_BROKEN") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"strings"
+)
+
+// MissingConfigError is returned when required configs are not configured. It lists all of them at once, so that
+// an administrator can fix the configuration in a single pass.
+type MissingConfigError struct {
+	// The full names of the missing configs, including the prefix.
+	Keys []string
+}
+
+func (e *MissingConfigError) Error() string {
+	return "missing required configs: " + strings.Join(e.Keys, ", ")
+}
+
+// Unwrap allows errors.Is(err, ErrConfigNotFound) to match a MissingConfigError.
+func (e *MissingConfigError) Unwrap() error {
+	return ErrConfigNotFound
+}
+
+// isRequired reports whether the field f must be configured.
+func (sc ServiceConfig) isRequired(f configField) bool {
+	if _, ok := sc.defaultOption(f); ok || f.opts.has("default_func") || f.opts.has("provider") ||
+		f.opts.has("compute") {
+		return false
+	}
+
+	return sc.RequireAll || f.opts.has("required")
+}
+
+// CheckRequired verifies that every required config of the struct pointed by obj is configured, without parsing
+// any value or modifying obj. Values are looked up as ParseTo would, with the per-field options such as `trim` and
+// `emptyunset`, so a config that ParseTo would report as missing is reported here too. It is meant as a fast
+// preflight check, e.g. for orchestration health checks. When configs are missing, a MissingConfigError listing all
+// of them is returned.
+func (sc ServiceConfig) CheckRequired(obj interface{}) error {
+	assertPointer(obj)
+	sc = sc.withStructSettings(obj)
+	_, err := sc.expandPrefix()
+	if err != nil {
+		return err
+	}
+
+	missing := make([]string, 0)
+	for _, f := range configFields(obj) {
+		if f.name == "" || !sc.isRequired(f) {
+			continue
+		}
+
+		fsc := sc.withOptions(f.opts.getOptions())
+		_, _, exist, err := fsc.resolveField(f)
+		if err != nil {
+			return sc.reformatParseError(f.name, err)
+		}
+		if !exist && f.opts.has("secret") {
+			_, _, exist, err = fsc.resolveSecret(f.name)
+			if err != nil {
+				return sc.reformatParseError(f.name, err)
+			}
+		}
+		if !exist {
+			missing = append(missing, sc.getConfigName(f.name))
+		}
+	}
+
+	if len(missing) > 0 {
+		return &MissingConfigError{Keys: missing}
+	}
+
+	return nil
+}
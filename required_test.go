@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestServiceConfig_CheckRequired(t *testing.T) {
+	type TestConfig struct {
+		Host     string `config:"HOST,required"`
+		Port     int    `config:"PORT,required"`
+		User     string `config:"USER,required"`
+		Timeout  int    `config:"TIMEOUT,required,default=30"`
+		Optional string `config:"OPTIONAL"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "REQUIRED",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("REQUIRED_HOST", "localhost")
+	t.Setenv("REQUIRED_PORT", "not a number")
+
+	n := &TestConfig{}
+	err := sc.CheckRequired(n)
+
+	var missingErr *MissingConfigError
+	if !errors.As(err, &missingErr) {
+		t.Fatalf("expected MissingConfigError, received: %v", err)
+	}
+	if !reflect.DeepEqual([]string{"REQUIRED_USER"}, missingErr.Keys) {
+		t.Fatalf("unexpected missing keys: %v", missingErr.Keys)
+	}
+	if !reflect.DeepEqual(&TestConfig{}, n) {
+		t.Fatalf("expected struct to be untouched, received: %v", n)
+	}
+
+	sc.RequireAll = true
+	t.Setenv("REQUIRED_PORT", "80")
+	err = sc.ParseTo(n)
+	if !errors.As(err, &missingErr) || !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("expected MissingConfigError, received: %v", err)
+	}
+	if !reflect.DeepEqual([]string{"REQUIRED_USER", "REQUIRED_OPTIONAL"}, missingErr.Keys) {
+		t.Fatalf("unexpected missing keys: %v", missingErr.Keys)
+	}
+}
+
+func TestServiceConfig_CheckRequired_options(t *testing.T) {
+	type TestConfig struct {
+		Host string `config:"HOST,required,emptyunset"`
+		User string `config:"USER,required,trim,emptyunset"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "REQUIREDOPTS",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("REQUIREDOPTS_HOST", "")
+	t.Setenv("REQUIREDOPTS_USER", "  ")
+
+	err := sc.CheckRequired(&TestConfig{})
+	var missingErr *MissingConfigError
+	if !errors.As(err, &missingErr) {
+		t.Fatalf("expected MissingConfigError, received: %v", err)
+	}
+
+	expected := []string{"REQUIREDOPTS_HOST", "REQUIREDOPTS_USER"}
+	if !reflect.DeepEqual(expected, missingErr.Keys) {
+		t.Fatalf("unexpected missing keys: %v", missingErr.Keys)
+	}
+
+	err = sc.ParseTo(&TestConfig{})
+	if !errors.As(err, &missingErr) || !reflect.DeepEqual(expected, missingErr.Keys) {
+		t.Fatalf("expected ParseTo to report the same missing keys, received: %v", err)
+	}
+}
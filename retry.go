@@ -0,0 +1,77 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRetriesExhausted is wrapped by the error returned by a Source created with WithRetry when every attempt failed.
+var ErrRetriesExhausted = errors.New("retries exhausted")
+
+// WithRetry returns a Source that looks keys up in source, retrying a failed lookup up to attempts times in total
+// before giving up, e.g. to ride out brief outages of a remote source. It waits backoff before the second attempt,
+// and twice as long before every following one.
+//
+// Only errors are retried: a key that source does not have is returned as not found right away. When all attempts
+// fail, the returned error wraps both ErrRetriesExhausted and the error of the last attempt. When source is a
+// WatchableSource, so is the returned Source.
+func WithRetry(source Source, attempts int, backoff time.Duration) Source {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	r := &retrySource{source: source, attempts: attempts, backoff: backoff}
+	if w, ok := source.(WatchableSource); ok {
+		return &retryWatchableSource{retrySource: r, watchable: w}
+	}
+
+	return r
+}
+
+type retrySource struct {
+	source   Source
+	attempts int
+	backoff  time.Duration
+}
+
+func (r *retrySource) Lookup(key string) (string, bool, error) {
+	return r.LookupContext(context.Background(), key)
+}
+
+// LookupContext is like Lookup, but ctx is given to the wrapped source, and waiting between attempts stops when ctx
+// is done.
+func (r *retrySource) LookupContext(ctx context.Context, key string) (string, bool, error) {
+	wait := r.backoff
+	for attempt := 1; ; attempt++ {
+		value, found, err := sourceLookup(ctx, r.source, key)
+		if err == nil {
+			return value, found, nil
+		}
+		if attempt == r.attempts {
+			return "", false, fmt.Errorf("%w after %d attempts: %w", ErrRetriesExhausted, attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", false, fmt.Errorf("retry interrupted after %d attempts: %w", attempt, ctx.Err())
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// String returns the name of the wrapped source, so that provenance reports where configs really come from.
+func (r *retrySource) String() string {
+	return sourceName(r.source)
+}
+
+type retryWatchableSource struct {
+	*retrySource
+	watchable WatchableSource
+}
+
+func (r *retryWatchableSource) OnChange(fn func(key string)) {
+	r.watchable.OnChange(fn)
+}
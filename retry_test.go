@@ -0,0 +1,73 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type flakySource struct {
+	failures int
+	calls    int
+	values   map[string]string
+}
+
+func (f *flakySource) Lookup(key string) (string, bool, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return "", false, errors.New("connection reset")
+	}
+
+	value, found := f.values[key]
+	return value, found, nil
+}
+
+func TestWithRetry(t *testing.T) {
+	flaky := &flakySource{failures: 2, values: map[string]string{"RETRY_PORT": "8080"}}
+	sc := ServiceConfig{
+		Prefix:         "RETRY",
+		ArraySeparator: " ",
+		Sources:        []Source{WithRetry(flaky, 3, time.Millisecond)},
+	}
+
+	port, err := sc.GetInt("PORT")
+	if err != nil || port != 8080 {
+		t.Fatalf("expected value after retries, received: %v, %v", port, err)
+	}
+	if flaky.calls != 3 {
+		t.Fatalf("expected 3 calls, received %d", flaky.calls)
+	}
+
+	flaky.calls, flaky.failures = 0, 0
+	_, err = sc.GetString("MISSING")
+	if !errors.Is(err, ErrConfigNotFound) || flaky.calls != 1 {
+		t.Fatalf("expected missing key without retries, received: %v after %d calls", err, flaky.calls)
+	}
+
+	flaky.calls, flaky.failures = 0, 5
+	_, err = sc.GetString("PORT")
+	if !errors.Is(err, ErrRetriesExhausted) || flaky.calls != 3 {
+		t.Fatalf("expected ErrRetriesExhausted after 3 calls, received: %v after %d calls", err, flaky.calls)
+	}
+}
+
+func TestWithRetry_context(t *testing.T) {
+	flaky := &flakySource{failures: 10}
+	sc := ServiceConfig{
+		Prefix:         "RETRYCTX",
+		ArraySeparator: " ",
+		Sources:        []Source{WithRetry(flaky, 5, time.Hour)},
+	}
+
+	type TestConfig struct {
+		Port int `config:"PORT"`
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := sc.ParseToCtx(ctx, &TestConfig{})
+	if !errors.Is(err, context.DeadlineExceeded) || flaky.calls != 1 {
+		t.Fatalf("expected the wait to stop with ctx after 1 call, received %d calls: %v", flaky.calls, err)
+	}
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultSecretsDir is the directory where Docker Swarm mounts secrets, read for fields tagged with the `secret`
+// option when SecretsDir is empty.
+const DefaultSecretsDir = "/run/secrets"
+
+// resolveSecret reads the value of name from the secret file named after its full config name in lower case, e.g.
+// /run/secrets/myapp_db_password for DB_PASSWORD with Prefix "MYAPP". A trailing newline is removed. A missing file
+// is reported as not found, and the origin of the value is the path of the file.
+func (sc ServiceConfig) resolveSecret(name string) (string, string, bool, error) {
+	dir := sc.SecretsDir
+	if dir == "" {
+		dir = DefaultSecretsDir
+	}
+
+	path := filepath.Join(dir, strings.ToLower(sc.getConfigName(name)))
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", "", false, nil
+	}
+	if err != nil {
+		return "", "", false, err
+	}
+
+	configData, exist := sc.clean(strings.TrimRight(string(data), "\r\n"), true)
+	return configData, "file:" + path, exist, nil
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestServiceConfig_ParseTo_secret(t *testing.T) {
+	type TestConfig struct {
+		DBPassword string `config:"DB_PASSWORD,secret,required"`
+		APIToken   string `config:"API_TOKEN,secret,default=none"`
+		CacheKey   string `config:"CACHE_KEY,secret"`
+	}
+
+	dir := t.TempDir()
+	sc := ServiceConfig{
+		Prefix:         "SECRET",
+		ArraySeparator: " ",
+		SecretsDir:     dir,
+	}
+
+	err := os.WriteFile(filepath.Join(dir, "secret_db_password"), []byte("hunter2\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "secret_cache_key"), []byte("from-file"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SECRET_CACHE_KEY", "from-env")
+
+	n := &TestConfig{}
+	p, err := sc.ParseToWithProvenance(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{DBPassword: "hunter2", APIToken: "none", CacheKey: "from-env"}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+	if p["DBPassword"] != "file:"+filepath.Join(dir, "secret_db_password") {
+		t.Fatalf("unexpected provenance: %v", p)
+	}
+
+	err = sc.CheckRequired(&TestConfig{})
+	if err != nil {
+		t.Fatalf("expected the secret file to satisfy the required check, received: %v", err)
+	}
+
+	err = os.Remove(filepath.Join(dir, "secret_db_password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var missing *MissingConfigError
+	err = sc.ParseTo(&TestConfig{})
+	if !errors.As(err, &missing) || !reflect.DeepEqual(missing.Keys, []string{"SECRET_DB_PASSWORD"}) {
+		t.Fatalf("expected the missing secret to be required, received: %v", err)
+	}
+}
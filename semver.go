@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version, such as 1.4.2 or 2.0.0-rc.1. Versions are ordered with Compare, following the
+// precedence rules of Semantic Versioning 2.0.0. Version implements encoding.TextUnmarshaler, so ParseTo parses
+// Version fields with ParseVersion.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+	// Prerelease holds the dot-separated pre-release identifiers, e.g. "rc.1", or is empty for a release.
+	Prerelease string
+}
+
+// ParseVersion parses s as MAJOR.MINOR.PATCH, optionally followed by "-" and pre-release identifiers, and by "+" and
+// build metadata, which is ignored. A leading "v" is allowed, e.g. "v1.4.2".
+func ParseVersion(s string) (Version, error) {
+	core := strings.TrimPrefix(s, "v")
+	core, _, _ = strings.Cut(core, "+")
+	core, pre, hasPre := strings.Cut(core, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("version %q is not of the form MAJOR.MINOR.PATCH", s)
+	}
+
+	numbers := make([]int, 0, 3)
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || (len(p) > 1 && p[0] == '0') {
+			return Version{}, fmt.Errorf("version %q has an invalid number %q", s, p)
+		}
+		numbers = append(numbers, n)
+	}
+	if hasPre && (pre == "" || strings.Contains(pre, "..") || strings.HasPrefix(pre, ".") || strings.HasSuffix(pre, ".")) {
+		return Version{}, fmt.Errorf("version %q has an invalid pre-release %q", s, pre)
+	}
+
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2], Prerelease: pre}, nil
+}
+
+// Compare returns -1 when v is lower than other, 1 when it is higher, and 0 when they are equal. A pre-release is
+// lower than the release of the same version, e.g. 1.0.0-rc.1 < 1.0.0.
+func (v Version) Compare(other Version) int {
+	for _, c := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if c[0] != c[1] {
+			return compareInts(c[0], c[1])
+		}
+	}
+
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	}
+
+	a, b := strings.Split(v.Prerelease, "."), strings.Split(other.Prerelease, ".")
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := comparePrerelease(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+
+	return compareInts(len(a), len(b))
+}
+
+// comparePrerelease compares two pre-release identifiers: numeric identifiers numerically and lower than
+// alphanumeric ones, which are compared in ASCII order.
+func comparePrerelease(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return compareInts(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// String returns the version as MAJOR.MINOR.PATCH, followed by the pre-release if any, without a leading "v".
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+
+	return s
+}
+
+func (v *Version) UnmarshalText(text []byte) error {
+	parsed, err := ParseVersion(string(text))
+	if err != nil {
+		return err
+	}
+
+	*v = parsed
+	return nil
+}
+
+// GetSemver returns the config value parsed with ParseVersion, e.g. to gate features on a minimum version. ParseTo
+// parses Version fields the same way, and checks them against the `minversion` option, e.g.
+// `config:"API_VERSION,minversion=1.2.0"`.
+func (sc ServiceConfig) GetSemver(name string, opts ...GetOption) (Version, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return Version{}, err
+	}
+	if !exist {
+		return Version{}, ErrConfigNotFound
+	}
+
+	v, err := ParseVersion(configData)
+	if err != nil {
+		return Version{}, sc.reformatParseError(name, err)
+	}
+	return v, nil
+}
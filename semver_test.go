@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	v, err := ParseVersion("v1.4.2-rc.1+build.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Version{Major: 1, Minor: 4, Patch: 2, Prerelease: "rc.1"}
+	if v != expected || v.String() != "1.4.2-rc.1" {
+		t.Fatalf("decoded version is not the same with expectation, received: %v, expected: %v", v, expected)
+	}
+
+	for _, invalid := range []string{"", "1.2", "1.2.3.4", "1.02.3", "1.x.3", "1.2.3-", "1.2.3-rc..1"} {
+		_, err := ParseVersion(invalid)
+		if err == nil {
+			t.Fatalf("expected an error for %q", invalid)
+		}
+	}
+
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.2.0", "2.0.0"}
+	for i := 1; i < len(ordered); i++ {
+		a, _ := ParseVersion(ordered[i-1])
+		b, _ := ParseVersion(ordered[i])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 || a.Compare(a) != 0 {
+			t.Fatalf("expected %s < %s", a, b)
+		}
+	}
+}
+
+func TestServiceConfig_GetSemver(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "SEMVER",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("SEMVER_API_VERSION", "1.4.2")
+	v, err := sc.GetSemver("API_VERSION")
+	if err != nil || v != (Version{Major: 1, Minor: 4, Patch: 2}) {
+		t.Fatalf("unexpected version: %v, %v", v, err)
+	}
+
+	_, err = sc.GetSemver("MISSING")
+	if !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("expected ErrConfigNotFound, received: %v", err)
+	}
+
+	t.Setenv("SEMVER_API_VERSION", "latest")
+	_, err = sc.GetSemver("API_VERSION")
+	if err == nil || !strings.Contains(err.Error(), "SEMVER_API_VERSION") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+}
+
+func TestServiceConfig_ParseTo_minVersion(t *testing.T) {
+	type TestConfig struct {
+		APIVersion Version `config:"API_VERSION,minversion=1.2.0"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "MINVERSION",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("MINVERSION_API_VERSION", "1.10.0")
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil || n.APIVersion != (Version{Major: 1, Minor: 10}) {
+		t.Fatalf("unexpected version: %v, %v", n.APIVersion, err)
+	}
+
+	t.Setenv("MINVERSION_API_VERSION", "1.2.0-rc.1")
+	err = sc.ParseTo(n)
+	if err == nil || !strings.Contains(err.Error(), "lower than the minimum 1.2.0") {
+		t.Fatalf("expected a minimum version error, received: %v", err)
+	}
+
+	type InvalidField struct {
+		Name string `config:"API_VERSION,minversion=1.0.0"`
+	}
+	err = sc.ParseTo(&InvalidField{})
+	if err == nil || !strings.Contains(err.Error(), "only supported for Version fields") {
+		t.Fatalf("expected an unsupported field error, received: %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// NewFromServiceName returns a ServiceConfig whose Prefix is derived from the service name in the environment
+// variable envVar, e.g. SERVICE_NAME, so that a generic binary can be deployed as several services, each reading its
+// own configs. The name is upper-cased, and characters that cannot appear in environment variable names are replaced
+// with "_", so that "billing-api" gives the prefix "BILLING_API".
+//
+// A missing or empty envVar is an error. Other settings, such as ArraySeparator, are left for the caller to set.
+func NewFromServiceName(envVar string) (ServiceConfig, error) {
+	name, found := os.LookupEnv(envVar)
+	name = strings.TrimSpace(name)
+	if !found || name == "" {
+		return ServiceConfig{}, fmt.Errorf("cannot discover service name: %s is not set", envVar)
+	}
+
+	prefix := strings.Map(func(r rune) rune {
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(name))
+
+	return ServiceConfig{Prefix: prefix}, nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewFromServiceName(t *testing.T) {
+	t.Setenv("SVCNAME_TEST_NAME", "billing-api")
+	sc, err := NewFromServiceName("SVCNAME_TEST_NAME")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sc.Prefix != "BILLING_API" {
+		t.Fatalf("derived prefix is not the same with expectation, received: %s, expected: %s", sc.Prefix, "BILLING_API")
+	}
+
+	t.Setenv("BILLING_API_PORT", "8080")
+	port, err := sc.GetInt("PORT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port != 8080 {
+		t.Fatalf("expected 8080, received: %d", port)
+	}
+
+	t.Setenv("SVCNAME_TEST_NAME", " ")
+	_, err = NewFromServiceName("SVCNAME_TEST_NAME")
+	if err == nil || !strings.Contains(err.Error(), "SVCNAME_TEST_NAME") {
+		t.Fatalf("expected error naming the variable for an empty service name, received: %v", err)
+	}
+
+	_, err = NewFromServiceName("SVCNAME_TEST_MISSING")
+	if err == nil || !strings.Contains(err.Error(), "SVCNAME_TEST_MISSING") {
+		t.Fatalf("expected error naming the variable for a missing service name, received: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrValueTooLarge is wrapped by the error returned by a Source created with WithMaxValueSize when a value exceeds
+// the limit.
+var ErrValueTooLarge = errors.New("config value too large")
+
+// WithMaxValueSize returns a Source that looks keys up in source, returning an error wrapping ErrValueTooLarge and
+// naming the key when a value is longer than limit bytes, e.g. to guard against a misconfigured remote source
+// returning megabytes of data. The limit applies to every value separately. A limit of zero or less disables the
+// check. When source is a WatchableSource, so is the returned Source.
+func WithMaxValueSize(source Source, limit int) Source {
+	s := &sizeSource{source: source, limit: limit}
+	if w, ok := source.(WatchableSource); ok {
+		return &sizeWatchableSource{sizeSource: s, watchable: w}
+	}
+
+	return s
+}
+
+type sizeSource struct {
+	source Source
+	limit  int
+}
+
+func (s *sizeSource) Lookup(key string) (string, bool, error) {
+	return s.LookupContext(context.Background(), key)
+}
+
+// LookupContext is like Lookup, but ctx is given to the wrapped source.
+func (s *sizeSource) LookupContext(ctx context.Context, key string) (string, bool, error) {
+	value, found, err := sourceLookup(ctx, s.source, key)
+	if err != nil {
+		return "", false, err
+	}
+	if s.limit > 0 && len(value) > s.limit {
+		return "", false, fmt.Errorf("%w: %s is %d bytes, the limit is %d", ErrValueTooLarge, key, len(value), s.limit)
+	}
+
+	return value, found, nil
+}
+
+// String returns the name of the wrapped source, so that provenance reports where configs really come from.
+func (s *sizeSource) String() string {
+	return sourceName(s.source)
+}
+
+type sizeWatchableSource struct {
+	*sizeSource
+	watchable WatchableSource
+}
+
+func (s *sizeWatchableSource) OnChange(fn func(key string)) {
+	s.watchable.OnChange(fn)
+}
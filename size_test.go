@@ -0,0 +1,37 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithMaxValueSize(t *testing.T) {
+	source := MapSource{"SIZE_NAME": "orders", "SIZE_BLOB": strings.Repeat("x", 100)}
+	sc := ServiceConfig{
+		Prefix:         "SIZE",
+		ArraySeparator: " ",
+		Sources:        []Source{WithMaxValueSize(source, 10)},
+	}
+
+	name, err := sc.GetString("NAME")
+	if err != nil || name != "orders" {
+		t.Fatalf("expected value within the limit, received: %v, %v", name, err)
+	}
+
+	_, err = sc.GetString("BLOB")
+	if !errors.Is(err, ErrValueTooLarge) || !strings.Contains(err.Error(), "SIZE_BLOB") {
+		t.Fatalf("expected ErrValueTooLarge naming the key, received: %v", err)
+	}
+
+	_, err = sc.GetString("MISSING")
+	if !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("expected ErrConfigNotFound, received: %v", err)
+	}
+
+	sc.Sources = []Source{WithMaxValueSize(source, 0)}
+	blob, err := sc.GetString("BLOB")
+	if err != nil || len(blob) != 100 {
+		t.Fatalf("expected no limit, received: %d bytes, %v", len(blob), err)
+	}
+}
@@ -0,0 +1,54 @@
+//go:build go1.21
+
+package config
+
+import (
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServiceConfig_ParseTo_slogLevel(t *testing.T) {
+	type TestConfig struct {
+		Level      slog.Level         `config:"LOG_LEVEL"`
+		AuditLevel *slog.Level        `config:"AUDIT_LEVEL"`
+		Sampling   map[slog.Level]int `config:"SAMPLING"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "SLOG",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("SLOG_LOG_LEVEL", "warn")
+	t.Setenv("SLOG_AUDIT_LEVEL", "debug+2")
+	t.Setenv("SLOG_SAMPLING", "debug=0 error=100")
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auditLevel := slog.LevelDebug + 2
+	expected := &TestConfig{
+		Level:      slog.LevelWarn,
+		AuditLevel: &auditLevel,
+		Sampling:   map[slog.Level]int{slog.LevelDebug: 0, slog.LevelError: 100},
+	}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+
+	env := sc.ExportEnv(n)
+	if !reflect.DeepEqual(env[:2], []string{"SLOG_LOG_LEVEL=WARN", "SLOG_AUDIT_LEVEL=DEBUG+2"}) {
+		t.Fatalf("unexpected exported levels: %v", env)
+	}
+
+	t.Setenv("SLOG_LOG_LEVEL", "verbose")
+	err = sc.ParseTo(n)
+	if err == nil || !strings.Contains(err.Error(), "SLOG_LOG_LEVEL") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package config
+
+// Snapshot holds the configured values read by a struct at some point, as captured by Capture, to be replayed with
+// Restore. It only holds strings, so it can be saved, e.g. as JSON, and restored in another run.
+type Snapshot struct {
+	Entries []SnapshotEntry
+}
+
+// SnapshotEntry is a configured value held by a Snapshot.
+type SnapshotEntry struct {
+	// The full name of the config, including the prefix, e.g. "MYAPP_PORT".
+	Key string
+	// The value as configured, before being parsed.
+	Value string
+	// Whether the field is tagged with the `secure` option. The value is stored nonetheless, so that the Snapshot
+	// can be restored, and must be handled with care.
+	Secure bool
+}
+
+// Capture returns the configured values that ParseTo would read for the struct pointed by obj, in field declaration
+// order, e.g. to reproduce a config-dependent bug later with Restore. Only configured keys are captured: the
+// Environment overlay of a field when it is set, and every chunk of fields with the `chunked` option, from the overlay
+// when it has the first chunk. Defaults and
+// computed fields are left out, since they are derived again when restoring. The struct is not modified.
+func (sc ServiceConfig) Capture(obj interface{}) (Snapshot, error) {
+	assertPointer(obj)
+	sc = sc.withStructSettings(obj)
+
+	s := Snapshot{Entries: make([]SnapshotEntry, 0)}
+	for _, f := range configFields(obj) {
+		if f.name == "" || f.name == "-" || f.opts.has("compute") {
+			continue
+		}
+
+		secure := f.opts.has("secure")
+		if !f.opts.has("chunked") {
+			_, err := sc.capture(&s, f.name, secure)
+			if err != nil {
+				return Snapshot{}, sc.reformatParseError(f.name, err)
+			}
+			continue
+		}
+
+		_, err := sc.expandPrefix()
+		if err != nil {
+			return Snapshot{}, sc.reformatParseError(f.name, err)
+		}
+		base, err := sc.chunkBase(f.name)
+		if err != nil {
+			return Snapshot{}, sc.reformatParseError(f.name, err)
+		}
+
+		for i := 1; ; i++ {
+			found, err := sc.captureKey(&s, sc.chunkKey(base, i), secure)
+			if err != nil {
+				return Snapshot{}, sc.reformatParseError(f.name, err)
+			}
+			if !found {
+				break
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// capture adds the value of name to s, from its Environment overlay if set, and reports whether it was found.
+func (sc ServiceConfig) capture(s *Snapshot, name string, secure bool) (bool, error) {
+	_, err := sc.expandPrefix()
+	if err != nil {
+		return false, err
+	}
+
+	keys := []string{sc.getConfigName(name)}
+	if sc.Environment != "" {
+		keys = []string{sc.getConfigName(sc.environmentName(name)), sc.getConfigName(name)}
+	}
+
+	for _, key := range keys {
+		found, err := sc.captureKey(s, key, secure)
+		if err != nil || found {
+			return found, err
+		}
+	}
+
+	return false, nil
+}
+
+// captureKey adds the value of the fully composed key to s, and reports whether it was found.
+func (sc ServiceConfig) captureKey(s *Snapshot, key string, secure bool) (bool, error) {
+	configData, _, exist, err := sc.lookupKey(key)
+	if err != nil || !exist {
+		return false, err
+	}
+
+	s.Entries = append(s.Entries, SnapshotEntry{Key: key, Value: configData, Secure: secure})
+	return true, nil
+}
+
+// Restore parses the values held by s into the struct pointed by obj, as ParseTo would, reading them from s only,
+// and never from the environment or Sources. Values that were not configured when s was captured are treated as
+// not configured, so defaults apply as they did then.
+func (sc ServiceConfig) Restore(obj interface{}, s Snapshot) error {
+	values := make(MapSource)
+	for _, e := range s.Entries {
+		values[e.Key] = e.Value
+	}
+
+	sc = sc.Clone()
+	sc.Sources = []Source{values}
+	sc.NoExtraKeys = false
+	return sc.ParseTo(obj)
+}
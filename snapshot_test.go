@@ -0,0 +1,68 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestServiceConfig_Capture(t *testing.T) {
+	type TestConfig struct {
+		Host     string `config:"HOST,default=localhost"`
+		Port     int    `config:"PORT"`
+		Password string `config:"PASSWORD,secure"`
+		Cert     string `config:"CERT,chunked"`
+		URL      string `config:"-,compute=http://{HOST}:{PORT}"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "SNAPSHOT",
+		ArraySeparator: " ",
+		Environment:    "staging",
+	}
+
+	t.Setenv("SNAPSHOT_PORT", "8080")
+	t.Setenv("SNAPSHOT_STAGING_PORT", "9090")
+	t.Setenv("SNAPSHOT_PASSWORD", "hunter2")
+	t.Setenv("SNAPSHOT_CERT_1", "abc")
+	t.Setenv("SNAPSHOT_CERT_2", "def")
+
+	s, err := sc.Capture(&TestConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Snapshot{Entries: []SnapshotEntry{
+		{Key: "SNAPSHOT_STAGING_PORT", Value: "9090"},
+		{Key: "SNAPSHOT_PASSWORD", Value: "hunter2", Secure: true},
+		{Key: "SNAPSHOT_CERT_1", Value: "abc"},
+		{Key: "SNAPSHOT_CERT_2", Value: "def"},
+	}}
+	if !reflect.DeepEqual(s, expected) {
+		t.Fatalf("captured snapshot is not the same with expectation, received: %v, expected: %v", s, expected)
+	}
+
+	saved, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Snapshot
+	err = json.Unmarshal(saved, &loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SNAPSHOT_STAGING_PORT", "7070")
+	t.Setenv("SNAPSHOT_HOST", "db")
+
+	n := &TestConfig{}
+	err = sc.Restore(n, loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := &TestConfig{Host: "localhost", Port: 9090, Password: "hunter2", Cert: "abcdef", URL: "http://localhost:9090"}
+	if !reflect.DeepEqual(n, restored) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, restored)
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+)
+
+// GetStringArraySorted is like GetStringArray, but returns the elements sorted lexically, e.g. so that a list whose
+// order does not matter hashes and compares the same however it is written. An empty value yields an empty slice.
+// ParseTo applies the same to slice fields tagged with the `sorted` option.
+func (sc ServiceConfig) GetStringArraySorted(name string, opts ...GetOption) ([]string, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+	if configData == "" {
+		return []string{}, nil
+	}
+
+	configDataArray, err := sc.split(configData)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(configDataArray)
+	return configDataArray, nil
+}
+
+// GetIntArraySorted is like GetIntArray, but returns the elements sorted numerically like GetStringArraySorted.
+func (sc ServiceConfig) GetIntArraySorted(name string, opts ...GetOption) ([]int, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+	if configData == "" {
+		return []int{}, nil
+	}
+
+	casted, err := sc.parseIntArray(name, configData)
+	if err != nil {
+		return nil, err
+	}
+	sort.Ints(casted)
+	return casted, nil
+}
+
+// sortable reports whether the elements of slices of type t can be sorted by sortField.
+func sortable(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+
+	switch t.Elem().Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// sortField sorts the slice held by field in ascending order, lexically for strings and numerically otherwise. The
+// type of field must be sortable.
+func sortField(field reflect.Value) {
+	sort.SliceStable(field.Interface(), func(i, j int) bool {
+		a, b := field.Index(i), field.Index(j)
+		switch a.Kind() {
+		case reflect.String:
+			return a.String() < b.String()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return a.Uint() < b.Uint()
+		case reflect.Float32, reflect.Float64:
+			return a.Float() < b.Float()
+		default:
+			return a.Int() < b.Int()
+		}
+	})
+}
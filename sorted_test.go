@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServiceConfig_GetStringArraySorted(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "SORTED",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("SORTED_HOSTS", "c a b")
+	t.Setenv("SORTED_PORTS", "443 80 8080")
+	t.Setenv("SORTED_EMPTY", "")
+
+	hosts, err := sc.GetStringArraySorted("HOSTS")
+	if err != nil || !reflect.DeepEqual(hosts, []string{"a", "b", "c"}) {
+		t.Fatalf("unexpected sorted strings: %v, %v", hosts, err)
+	}
+
+	ports, err := sc.GetIntArraySorted("PORTS")
+	if err != nil || !reflect.DeepEqual(ports, []int{80, 443, 8080}) {
+		t.Fatalf("expected ports to be sorted numerically, received: %v, %v", ports, err)
+	}
+
+	empty, err := sc.GetStringArraySorted("EMPTY")
+	if err != nil || empty == nil || len(empty) != 0 {
+		t.Fatalf("expected an empty slice, received: %v, %v", empty, err)
+	}
+	emptyInts, err := sc.GetIntArraySorted("EMPTY")
+	if err != nil || emptyInts == nil || len(emptyInts) != 0 {
+		t.Fatalf("expected an empty slice, received: %v, %v", emptyInts, err)
+	}
+
+	_, err = sc.GetStringArraySorted("MISSING")
+	if !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("expected ErrConfigNotFound, received: %v", err)
+	}
+}
+
+func TestServiceConfig_ParseTo_sorted(t *testing.T) {
+	type TestConfig struct {
+		Hosts    []string        `config:"HOSTS,sorted"`
+		Ports    []int           `config:"PORTS,sorted,unique"`
+		Timeouts []time.Duration `config:"TIMEOUTS,sorted"`
+		Empty    []string        `config:"EMPTY,sorted"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "SORTEDS",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("SORTEDS_HOSTS", "c a b")
+	t.Setenv("SORTEDS_PORTS", "443 80 443 8080")
+	t.Setenv("SORTEDS_TIMEOUTS", "1m 5s 100ms")
+	t.Setenv("SORTEDS_EMPTY", "")
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{
+		Hosts:    []string{"a", "b", "c"},
+		Ports:    []int{80, 443, 8080},
+		Timeouts: []time.Duration{100 * time.Millisecond, 5 * time.Second, time.Minute},
+		Empty:    []string{},
+	}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+
+	type InvalidField struct {
+		Name string `config:"HOSTS,sorted"`
+	}
+	err = sc.ParseTo(&InvalidField{})
+	if err == nil || !strings.Contains(err.Error(), "sorted is only supported") {
+		t.Fatalf("expected an unsupported field error, received: %v", err)
+	}
+}
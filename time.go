@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	timeLayoutsMu sync.RWMutex
+	timeLayouts   = map[string]string{
+		"rfc3339":     time.RFC3339,
+		"rfc3339nano": time.RFC3339Nano,
+		"rfc1123":     time.RFC1123,
+		"rfc1123z":    time.RFC1123Z,
+		"datetime":    time.DateTime,
+		"dateonly":    time.DateOnly,
+		"timeonly":    time.TimeOnly,
+	}
+)
+
+// RegisterTimeLayout registers layout under name, so that time.Time fields can refer to it with the `layout` tag
+// option, e.g. after RegisterTimeLayout("compact", "20060102"), a field tagged `config:"DATE,layout=compact"` is
+// parsed with the layout "20060102". Registering an existing name replaces its layout.
+//
+// The names rfc3339, rfc3339nano, rfc1123, rfc1123z, datetime, dateonly and timeonly are registered with the
+// matching layouts of the time package.
+func RegisterTimeLayout(name, layout string) {
+	timeLayoutsMu.Lock()
+	defer timeLayoutsMu.Unlock()
+
+	timeLayouts[name] = layout
+}
+
+// timeLayout returns the layout registered under name, or time.RFC3339 when name is empty.
+func timeLayout(name string) (string, error) {
+	if name == "" {
+		return time.RFC3339, nil
+	}
+
+	timeLayoutsMu.RLock()
+	defer timeLayoutsMu.RUnlock()
+
+	layout, ok := timeLayouts[name]
+	if !ok {
+		return "", fmt.Errorf("unknown time layout %q, register it with RegisterTimeLayout", name)
+	}
+	return layout, nil
+}
+
+// GetTimeArray returns the config value split by ArraySeparator, with every element parsed with layout, e.g.
+// time.RFC3339 or time.DateOnly. An empty layout is time.RFC3339. ParseTo does the same for []time.Time fields, with
+// the layout registered under the name given by the `layout` option, see RegisterTimeLayout.
+func (sc ServiceConfig) GetTimeArray(name string, layout string, opts ...GetOption) ([]time.Time, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+
+	return sc.parseTimeArray(name, configData, layout)
+}
+
+// GetTimeArrayWithDefault returns the config value parsed as GetTimeArray does, or defaultValue when the config does
+// not exist.
+func (sc ServiceConfig) GetTimeArrayWithDefault(name string, layout string, defaultValue []time.Time, opts ...GetOption) ([]time.Time, error) {
+	sc = sc.withOptions(opts)
+	return arrayWithDefault(sc, name, defaultValue, func(configData string) ([]time.Time, error) {
+		return sc.parseTimeArray(name, configData, layout)
+	})
+}
+
+func (sc ServiceConfig) parseTimeArray(name string, configData string, layout string) ([]time.Time, error) {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	configDataArray, err := sc.split(configData)
+	if err != nil {
+		return nil, err
+	}
+
+	times := make([]time.Time, 0, len(configDataArray))
+	for i, v := range configDataArray {
+		t, err := time.Parse(layout, v)
+		if err != nil {
+			return nil, fmt.Errorf("config name %s element %d cannot be parsed: %w", name, i, err)
+		}
+		times = append(times, t)
+	}
+
+	return times, nil
+}
+
+// GetLocation returns the time zone named by the config value, such as "America/New_York", loaded with
+// time.LoadLocation.
+func (sc ServiceConfig) GetLocation(name string, opts ...GetOption) (*time.Location, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+
+	loc, err := time.LoadLocation(configData)
+	if err != nil {
+		return nil, sc.reformatParseError(name, err)
+	}
+	return loc, nil
+}
+
+func (sc ServiceConfig) GetLocationWithDefault(name string, defaultValue *time.Location, opts ...GetOption) (*time.Location, error) {
+	sc = sc.withOptions(opts)
+	loc, err := sc.GetLocation(name)
+	if errors.Is(err, ErrConfigNotFound) {
+		return defaultValue, nil
+	}
+	return loc, err
+}
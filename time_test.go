@@ -0,0 +1,156 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServiceConfig_GetLocation(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "LOCATION",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("LOCATION_TZ", "America/New_York")
+	loc, err := sc.GetLocation("TZ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.String() != "America/New_York" {
+		t.Fatalf("unexpected location: %s", loc)
+	}
+
+	loc, err = sc.GetLocationWithDefault("MISSING", time.UTC)
+	if err != nil || loc != time.UTC {
+		t.Fatalf("expected default location, received: %v, %v", loc, err)
+	}
+
+	type TestConfig struct {
+		TZ *time.Location `config:"TZ"`
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.TZ.String() != "America/New_York" {
+		t.Fatalf("unexpected decoded location: %s", n.TZ)
+	}
+
+	t.Setenv("LOCATION_TZ", "Nowhere/Unknown")
+	_, err = sc.GetLocation("TZ")
+	if err == nil || !strings.Contains(err.Error(), "LOCATION_TZ") {
+		t.Fatalf("expected error naming the key, received: %v", err)
+	}
+}
+
+func TestServiceConfig_ParseTo_timeLayout(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix: "LAYOUT",
+	}
+
+	RegisterTimeLayout("compact", "20060102")
+
+	t.Setenv("LAYOUT_START", "2024-03-01T10:00:00Z")
+	t.Setenv("LAYOUT_DATE", "20240301")
+
+	type TestConfig struct {
+		Start time.Time `config:"START"`
+		Date  time.Time `config:"DATE,layout=compact"`
+	}
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !n.Start.Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected start: %s", n.Start)
+	}
+	if !n.Date.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected date: %s", n.Date)
+	}
+
+	type UnknownConfig struct {
+		Date time.Time `config:"DATE,layout=nope"`
+	}
+
+	err = sc.ParseTo(&UnknownConfig{})
+	if err == nil || !strings.Contains(err.Error(), "LAYOUT_DATE") || !strings.Contains(err.Error(), "nope") {
+		t.Fatalf("expected unknown layout error, received: %v", err)
+	}
+}
+
+func TestServiceConfig_GetTimeArray(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "TIMEARRAY",
+		ArraySeparator: ",",
+	}
+
+	t.Setenv("TIMEARRAY_WINDOWS", "2024-03-01T02:00:00Z,2024-04-01T02:00:00Z")
+	windows, err := sc.GetTimeArray("WINDOWS", time.RFC3339)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []time.Time{
+		time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 1, 2, 0, 0, 0, time.UTC),
+	}
+	if len(windows) != len(expect) || !windows[0].Equal(expect[0]) || !windows[1].Equal(expect[1]) {
+		t.Fatalf("parsed array is not the same with expectation, received: %v, expected: %v", windows, expect)
+	}
+
+	t.Setenv("TIMEARRAY_BAD", "2024-03-01,tomorrow")
+	_, err = sc.GetTimeArray("BAD", time.DateOnly)
+	if err == nil || !strings.Contains(err.Error(), "BAD element 1") {
+		t.Fatalf("expected error naming the key and index, received: %v", err)
+	}
+
+	_, err = sc.GetTimeArray("MISSING", "")
+	if err != ErrConfigNotFound {
+		t.Fatalf("expected ErrConfigNotFound, received: %v", err)
+	}
+
+	defaults := []time.Time{expect[0]}
+	times, err := sc.GetTimeArrayWithDefault("MISSING", "", defaults)
+	if err != nil || len(times) != 1 || !times[0].Equal(expect[0]) {
+		t.Fatalf("expected default array, received: %v, %v", times, err)
+	}
+
+	t.Setenv("TIMEARRAY_EMPTY", "")
+	times, err = sc.GetTimeArrayWithDefault("EMPTY", "", defaults)
+	if err != nil || times == nil || len(times) != 0 {
+		t.Fatalf("expected empty array, received: %v, %v", times, err)
+	}
+
+	type TestConfig struct {
+		Windows []time.Time `config:"WINDOWS"`
+		Dates   []time.Time `config:"DATES,layout=dateonly"`
+	}
+
+	t.Setenv("TIMEARRAY_DATES", "2024-03-01,2024-04-01")
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(n.Windows) != 2 || !n.Windows[1].Equal(expect[1]) {
+		t.Fatalf("unexpected decoded windows: %v", n.Windows)
+	}
+	if len(n.Dates) != 2 || !n.Dates[0].Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected decoded dates: %v", n.Dates)
+	}
+
+	type BadConfig struct {
+		Bad []time.Time `config:"BAD,layout=dateonly"`
+	}
+
+	err = sc.ParseTo(&BadConfig{})
+	if err == nil || !strings.Contains(err.Error(), "BAD element 1") {
+		t.Fatalf("expected error naming the key and index, received: %v", err)
+	}
+}
@@ -0,0 +1,7 @@
+module github.com/potatobeansco/go-config/tomlsource
+
+go 1.21.0
+
+require github.com/potatobeansco/go-config v1.1.0
+
+require github.com/BurntSushi/toml v1.6.0
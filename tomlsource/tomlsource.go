@@ -0,0 +1,27 @@
+// Package tomlsource provides a config.Source reading from TOML documents. It is kept in its own module so that the
+// TOML dependency is only required by services that use it.
+package tomlsource
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	config "github.com/potatobeansco/go-config"
+)
+
+// NewTOMLSource reads the TOML document in the file at path and returns it as a config.Source. Tables are flattened
+// into underscore-joined, upper-cased keys and arrays are joined with arraySeparator, the same way as
+// config.NewJSONSource does. For example, the document below provides MYAPP_DB_HOST and MYAPP_DB_REPLICAS:
+//
+//	[myapp.db]
+//	host = "localhost"
+//	replicas = ["a", "b"]
+func NewTOMLSource(path string, arraySeparator string) (config.Source, error) {
+	doc := make(map[string]interface{})
+	_, err := toml.DecodeFile(path, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s: %w", path, err)
+	}
+
+	return config.MapSource(config.Flatten(doc, arraySeparator)), nil
+}
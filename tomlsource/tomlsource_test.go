@@ -0,0 +1,65 @@
+package tomlsource
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	config "github.com/potatobeansco/go-config"
+)
+
+func TestNewTOMLSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	err := os.WriteFile(path, []byte(`
+[myapp]
+port = 8080
+ratio = 0.5
+
+[myapp.db]
+host = "localhost"
+replicas = ["a", "b"]
+
+[[myapp.servers]]
+name = "first"
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := NewTOMLSource(path, " ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type TestConfig struct {
+		Port       int      `config:"PORT"`
+		Ratio      float64  `config:"RATIO"`
+		DBHost     string   `config:"DB_HOST"`
+		DBReplicas []string `config:"DB_REPLICAS"`
+		ServerName string   `config:"SERVERS_0_NAME"`
+	}
+
+	sc := config.ServiceConfig{
+		Prefix:         "MYAPP",
+		ArraySeparator: " ",
+		Sources:        []config.Source{source},
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := &TestConfig{
+		Port:       8080,
+		Ratio:      0.5,
+		DBHost:     "localhost",
+		DBReplicas: []string{"a", "b"},
+		ServerName: "first",
+	}
+	if !reflect.DeepEqual(expect, n) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expect)
+	}
+}
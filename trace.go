@@ -0,0 +1,28 @@
+package config
+
+import (
+	"fmt"
+)
+
+// tracef writes a line to Trace, if set.
+func (sc ServiceConfig) tracef(format string, args ...interface{}) {
+	if sc.Trace == nil {
+		return
+	}
+
+	_, _ = fmt.Fprintf(sc.Trace, format+"\n", args...)
+}
+
+// traceField writes the value assigned to f, and where it came from, to Trace, if set. The value of a field tagged
+// with the `secure` option is masked.
+func (sc ServiceConfig) traceField(f configField, origin string) {
+	if sc.Trace == nil {
+		return
+	}
+
+	var value interface{} = "********"
+	if !f.opts.has("secure") {
+		value = f.value.Interface()
+	}
+	sc.tracef("assign %s = %v (from %s)", f.field.Name, value, origin)
+}
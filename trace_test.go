@@ -0,0 +1,50 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestServiceConfig_Trace(t *testing.T) {
+	type TestConfig struct {
+		Port     int    `config:"PORT"`
+		Host     string `config:"HOST"`
+		Password string `config:"PASSWORD,secure"`
+		URL      string `config:"-,compute=http://{HOST}:{PORT}"`
+	}
+
+	var b strings.Builder
+	sc := ServiceConfig{
+		Prefix:         "TRACE",
+		ArraySeparator: " ",
+		Environment:    "staging",
+		Sources:        []Source{MapSource{"TRACE_PASSWORD": "hunter2"}, EnvSource{}},
+		Trace:          &b,
+	}
+
+	t.Setenv("TRACE_STAGING_PORT", "9090")
+
+	err := sc.ParseTo(&TestConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		"lookup TRACE_STAGING_PORT: found in env\n",
+		"assign Port = 9090 (from env)\n",
+		"lookup TRACE_STAGING_HOST: not found\n",
+		"lookup TRACE_HOST: not found\n",
+		"keep Host: not configured\n",
+		"lookup TRACE_STAGING_PASSWORD: not found\n",
+		"lookup TRACE_PASSWORD: found in config.MapSource\n",
+		"assign Password = ******** (from config.MapSource)\n",
+		"assign URL = http://:9090 (from computed)\n",
+	} {
+		if !strings.Contains(b.String(), line) {
+			t.Fatalf("expected trace to contain %q, received:\n%s", line, b.String())
+		}
+	}
+	if strings.Contains(b.String(), "hunter2") {
+		t.Fatalf("expected secure values to be masked, received:\n%s", b.String())
+	}
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"reflect"
+)
+
+// GetStringArrayUnique is like GetStringArray, but removes duplicate elements, keeping the first occurrence of
+// each, e.g. "a b a c" yields [a b c]. An empty value yields an empty slice. ParseTo applies the same to slice fields
+// tagged with the `unique` option.
+func (sc ServiceConfig) GetStringArrayUnique(name string, opts ...GetOption) ([]string, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+	if configData == "" {
+		return []string{}, nil
+	}
+
+	configDataArray, err := sc.split(configData)
+	if err != nil {
+		return nil, err
+	}
+	return unique(configDataArray), nil
+}
+
+// GetIntArrayUnique is like GetIntArray, but removes duplicate elements like GetStringArrayUnique.
+func (sc ServiceConfig) GetIntArrayUnique(name string, opts ...GetOption) ([]int, error) {
+	sc = sc.withOptions(opts)
+	configData, exist, err := sc.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrConfigNotFound
+	}
+	if configData == "" {
+		return []int{}, nil
+	}
+
+	casted, err := sc.parseIntArray(name, configData)
+	if err != nil {
+		return nil, err
+	}
+	return unique(casted), nil
+}
+
+// unique returns the elements of s without duplicates, in order of first occurrence.
+func unique[T comparable](s []T) []T {
+	seen := make(map[T]bool, len(s))
+	result := make([]T, 0, len(s))
+	for _, v := range s {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// uniqueField removes duplicate elements from the slice held by field, which must have comparable elements.
+func uniqueField(field reflect.Value) {
+	seen := make(map[interface{}]bool, field.Len())
+	result := reflect.MakeSlice(field.Type(), 0, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		v := field.Index(i)
+		if !seen[v.Interface()] {
+			seen[v.Interface()] = true
+			result = reflect.Append(result, v)
+		}
+	}
+
+	field.Set(result)
+}
+
+// firstDuplicate returns the index of the first element of the slice held by field that equals an earlier one.
+func firstDuplicate(field reflect.Value) (int, bool) {
+	seen := make(map[interface{}]bool, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		v := field.Index(i).Interface()
+		if seen[v] {
+			return i, true
+		}
+		seen[v] = true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServiceConfig_GetArrayUnique(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "UNIQUE",
+		ArraySeparator: " ",
+	}
+
+	t.Setenv("UNIQUE_HOSTS", "b a b c a")
+	t.Setenv("UNIQUE_PORTS", "80 443 80")
+	t.Setenv("UNIQUE_EMPTY", "")
+
+	hosts, err := sc.GetStringArrayUnique("HOSTS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(hosts, []string{"b", "a", "c"}) {
+		t.Fatalf("unexpected hosts: %v", hosts)
+	}
+
+	ports, err := sc.GetIntArrayUnique("PORTS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ports, []int{80, 443}) {
+		t.Fatalf("unexpected ports: %v", ports)
+	}
+
+	empty, err := sc.GetStringArrayUnique("EMPTY")
+	if err != nil || empty == nil || len(empty) != 0 {
+		t.Fatalf("expected empty slice, received: %#v, %v", empty, err)
+	}
+
+	emptyInts, err := sc.GetIntArrayUnique("EMPTY")
+	if err != nil || emptyInts == nil || len(emptyInts) != 0 {
+		t.Fatalf("expected empty slice, received: %#v, %v", emptyInts, err)
+	}
+
+	type TestConfig struct {
+		Hosts []string `config:"HOSTS,unique"`
+		Ports []int    `config:"PORTS,unique"`
+		Empty []string `config:"EMPTY,unique"`
+	}
+
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TestConfig{Hosts: []string{"b", "a", "c"}, Ports: []int{80, 443}, Empty: []string{}}
+	if !reflect.DeepEqual(n, expected) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expected)
+	}
+}
+
+func TestServiceConfig_ParseTo_distinct(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "DISTINCT",
+		ArraySeparator: " ",
+	}
+
+	type TestConfig struct {
+		Hosts []string `config:"HOSTS,distinct"`
+		Ports []int    `config:"PORTS,distinct"`
+	}
+
+	t.Setenv("DISTINCT_HOSTS", "a b c")
+	t.Setenv("DISTINCT_PORTS", "80 443")
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := &TestConfig{Hosts: []string{"a", "b", "c"}, Ports: []int{80, 443}}
+	if !reflect.DeepEqual(expect, n) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, expect)
+	}
+
+	t.Setenv("DISTINCT_PORTS", "80 443 80")
+	err = sc.ParseTo(&TestConfig{})
+	if err == nil || !strings.Contains(err.Error(), "DISTINCT_PORTS") || !strings.Contains(err.Error(), "element 2 (80) is a duplicate") {
+		t.Fatalf("expected error naming the key and the duplicate, received: %v", err)
+	}
+
+	type SecureConfig struct {
+		Tokens []string `config:"TOKENS,distinct,secure"`
+	}
+
+	t.Setenv("DISTINCT_TOKENS", "s3cret other s3cret")
+	err = sc.ParseTo(&SecureConfig{})
+	if err == nil || strings.Contains(err.Error(), "s3cret") {
+		t.Fatalf("expected error without the secure value, received: %v", err)
+	}
+
+	type UniqueConfig struct {
+		Tokens []string `config:"TOKENS,distinct,unique"`
+	}
+
+	u := &UniqueConfig{}
+	err = sc.ParseTo(u)
+	if err != nil {
+		t.Fatalf("expected duplicates to be removed before the check, received: %v", err)
+	}
+
+	type InvalidConfig struct {
+		Port int `config:"PORT,distinct"`
+	}
+
+	t.Setenv("DISTINCT_PORT", "80")
+	err = sc.ParseTo(&InvalidConfig{})
+	if err == nil || !strings.Contains(err.Error(), "distinct is only supported") {
+		t.Fatalf("expected error for a distinct non-slice field, received: %v", err)
+	}
+}
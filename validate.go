@@ -0,0 +1,198 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidateAgainst checks that the values of the struct pointed by live satisfy the constraints declared by the
+// `config` tags of the struct pointed by schema, so that validation rules can be kept apart from the struct used at
+// runtime. Fields are matched by config name, and every config of schema must have a field in live.
+//
+// A required field of schema, see ParseTo, must have a non-zero value in live, and the `oneof`, `pattern`, `min`,
+// `max`, `minversion`, `len`, `minlen`, `maxlen` and `distinct` options of schema are checked as ParseTo would check
+// them. All violations are returned together, joined with errors.Join. The environment is never read.
+func (sc ServiceConfig) ValidateAgainst(schema, live interface{}) error {
+	assertPointer(schema)
+	assertPointer(live)
+	sc = sc.withStructSettings(schema)
+
+	liveFields := make(map[string]configField)
+	for _, f := range configFields(live) {
+		liveFields[f.name] = f
+	}
+
+	errs := make([]error, 0)
+	for _, s := range configFields(schema) {
+		if s.name == "" || s.name == "-" {
+			continue
+		}
+
+		f, ok := liveFields[s.name]
+		if !ok {
+			errs = append(errs, sc.reformatParseError(s.name, errors.New("no field of the live struct has this config")))
+			continue
+		}
+
+		if f.value.IsZero() {
+			if sc.isRequired(s) {
+				errs = append(errs, sc.reformatParseError(s.name, fmt.Errorf("required, but not set: %w", ErrConfigNotFound)))
+			}
+			continue
+		}
+
+		f.opts = s.opts
+		err := validateField(f)
+		if err != nil {
+			errs = append(errs, sc.reformatParseError(s.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateField checks the value of f against the constraints given by its `oneof`, `pattern`, `min`, `max`,
+// `minversion`, `len`, `minlen`, `maxlen` and `distinct` options. Values of fields tagged with the `secure` option are
+// left out of the returned error.
+func validateField(f configField) error {
+	value := fmt.Sprintf("%v", f.value.Interface())
+	shown := value
+	if f.opts.has("secure") {
+		shown = "********"
+	}
+
+	if oneof, ok := f.opts.get("oneof"); ok {
+		allowed := strings.Split(oneof, "|")
+		found := false
+		for _, a := range allowed {
+			if a == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("value %s is not one of %s", shown, strings.Join(allowed, ", "))
+		}
+	}
+
+	if pattern, ok := f.opts.get("pattern"); ok {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return fmt.Errorf("invalid pattern `%s`: %w", pattern, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("value %s does not match pattern `%s`", shown, pattern)
+		}
+	}
+
+	for _, bound := range []string{"min", "max"} {
+		limit, ok := f.opts.get(bound)
+		if !ok {
+			continue
+		}
+
+		n, ok := numericValue(f.value)
+		if !ok {
+			return fmt.Errorf("%s is only supported for numeric fields, not %s", bound, f.value.Type())
+		}
+
+		l, err := parseBound(f.value.Type(), limit)
+		if err != nil {
+			return fmt.Errorf("invalid %s `%s`: %w", bound, limit, err)
+		}
+
+		if bound == "min" && n < l {
+			return fmt.Errorf("value %s is less than the minimum %s", shown, limit)
+		}
+		if bound == "max" && n > l {
+			return fmt.Errorf("value %s is greater than the maximum %s", shown, limit)
+		}
+	}
+
+	if floor, ok := f.opts.get("minversion"); ok {
+		v, ok := f.value.Interface().(Version)
+		if !ok {
+			return fmt.Errorf("minversion is only supported for Version fields, not %s", f.value.Type())
+		}
+
+		minimum, err := ParseVersion(floor)
+		if err != nil {
+			return fmt.Errorf("invalid minversion `%s`: %w", floor, err)
+		}
+		if v.Compare(minimum) < 0 {
+			return fmt.Errorf("version %s is lower than the minimum %s", v, minimum)
+		}
+	}
+
+	for _, bound := range []string{"len", "minlen", "maxlen"} {
+		limit, ok := f.opts.get(bound)
+		if !ok {
+			continue
+		}
+
+		if f.value.Kind() != reflect.Slice {
+			return fmt.Errorf("%s is only supported for slice fields, not %s", bound, f.value.Type())
+		}
+
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return fmt.Errorf("invalid %s `%s`: %w", bound, limit, err)
+		}
+
+		n := f.value.Len()
+		switch {
+		case bound == "len" && n != l:
+			return fmt.Errorf("has %d elements, expected exactly %d", n, l)
+		case bound == "minlen" && n < l:
+			return fmt.Errorf("has %d elements, expected at least %d", n, l)
+		case bound == "maxlen" && n > l:
+			return fmt.Errorf("has %d elements, expected at most %d", n, l)
+		}
+	}
+
+	if f.opts.has("distinct") {
+		if f.value.Kind() != reflect.Slice || !f.value.Type().Elem().Comparable() {
+			return fmt.Errorf("distinct is only supported for slices of comparable elements, not %s", f.value.Type())
+		}
+
+		if i, ok := firstDuplicate(f.value); ok {
+			dup := fmt.Sprintf("%v", f.value.Index(i).Interface())
+			if f.opts.has("secure") {
+				dup = "********"
+			}
+			return fmt.Errorf("element %d (%s) is a duplicate", i, dup)
+		}
+	}
+
+	return nil
+}
+
+// numericValue returns the value of v as a float64 if it is of a numeric kind.
+func numericValue(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// parseBound parses the limit of a `min` or `max` option, which is a duration for time.Duration fields and a number
+// otherwise.
+func parseBound(t reflect.Type, limit string) (float64, error) {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(limit)
+		return float64(d), err
+	}
+
+	return strconv.ParseFloat(limit, 64)
+}
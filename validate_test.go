@@ -0,0 +1,163 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServiceConfig_ParseTo_validation(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "VALIDATE",
+		ArraySeparator: " ",
+	}
+
+	type TestConfig struct {
+		Mode    string        `config:"MODE,oneof=dev|prod"`
+		Name    string        `config:"NAME,pattern=[a-z]+"`
+		Port    int           `config:"PORT,min=1,max=65535"`
+		Timeout time.Duration `config:"TIMEOUT,min=1s,max=1m"`
+		Token   string        `config:"TOKEN,secure,pattern=[0-9a-f]+"`
+	}
+
+	t.Setenv("VALIDATE_MODE", "prod")
+	t.Setenv("VALIDATE_NAME", "orders")
+	t.Setenv("VALIDATE_PORT", "8080")
+	t.Setenv("VALIDATE_TIMEOUT", "30s")
+	t.Setenv("VALIDATE_TOKEN", "abc123")
+
+	n := &TestConfig{}
+	err := sc.ParseTo(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		key      string
+		value    string
+		expected string
+	}{
+		{"VALIDATE_MODE", "staging", "VALIDATE_MODE: value staging is not one of dev, prod"},
+		{"VALIDATE_NAME", "orders2", "VALIDATE_NAME: value orders2 does not match pattern `[a-z]+`"},
+		{"VALIDATE_PORT", "0", "VALIDATE_PORT: value 0 is less than the minimum 1"},
+		{"VALIDATE_PORT", "70000", "VALIDATE_PORT: value 70000 is greater than the maximum 65535"},
+		{"VALIDATE_TIMEOUT", "2m", "VALIDATE_TIMEOUT: value 2m0s is greater than the maximum 1m"},
+		{"VALIDATE_TOKEN", "secret!", "VALIDATE_TOKEN: value ******** does not match pattern `[0-9a-f]+`"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.key+"="+c.value, func(t *testing.T) {
+			t.Setenv(c.key, c.value)
+
+			err := sc.ParseTo(&TestConfig{})
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Fatalf("expected error %q, received: %v", c.expected, err)
+			}
+		})
+	}
+}
+
+func TestServiceConfig_ParseTo_length(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "LENGTH",
+		ArraySeparator: " ",
+	}
+
+	type TestConfig struct {
+		Replicas []string        `config:"REPLICAS,minlen=1,maxlen=3"`
+		Ports    []int           `config:"PORTS,len=2"`
+		Delays   []time.Duration `config:"DELAYS,maxlen=2"`
+	}
+
+	cases := []struct {
+		replicas string
+		ports    string
+		delays   string
+		expected string
+	}{
+		{"a b", "80 443", "1s", ""},
+		{"a b c d", "80 443", "1s", "LENGTH_REPLICAS: has 4 elements, expected at most 3"},
+		{"a", "80", "1s", "LENGTH_PORTS: has 1 elements, expected exactly 2"},
+		{"a", "80 443", "1s 2s 3s", "LENGTH_DELAYS: has 3 elements, expected at most 2"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.replicas+"/"+c.ports+"/"+c.delays, func(t *testing.T) {
+			t.Setenv("LENGTH_REPLICAS", c.replicas)
+			t.Setenv("LENGTH_PORTS", c.ports)
+			t.Setenv("LENGTH_DELAYS", c.delays)
+
+			err := sc.ParseTo(&TestConfig{})
+			if c.expected == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Fatalf("expected error %q, received: %v", c.expected, err)
+			}
+		})
+	}
+
+	type UnderConfig struct {
+		Replicas []string `config:"REPLICAS,minlen=2"`
+	}
+
+	t.Setenv("LENGTH_REPLICAS", "a")
+	err := sc.ParseTo(&UnderConfig{})
+	if err == nil || !strings.Contains(err.Error(), "LENGTH_REPLICAS: has 1 elements, expected at least 2") {
+		t.Fatalf("expected minlen error, received: %v", err)
+	}
+}
+
+func TestServiceConfig_ValidateAgainst(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "SCHEMA",
+		ArraySeparator: " ",
+	}
+
+	type Schema struct {
+		Mode  string `config:"MODE,oneof=dev|prod"`
+		Port  int    `config:"PORT,min=1,max=65535"`
+		Host  string `config:"HOST,required"`
+		Hosts []int  `config:"HOSTS,maxlen=1"`
+	}
+
+	type Live struct {
+		Mode  string   `config:"MODE"`
+		Port  int      `config:"PORT"`
+		Host  string   `config:"HOST"`
+		Hosts []int    `config:"HOSTS"`
+		Extra []string `config:"EXTRA"`
+	}
+
+	err := sc.ValidateAgainst(&Schema{}, &Live{Mode: "prod", Port: 8080, Host: "localhost", Hosts: []int{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sc.ValidateAgainst(&Schema{}, &Live{Mode: "staging", Port: 70000})
+	if err == nil {
+		t.Fatal("expected violations")
+	}
+
+	for _, expected := range []string{
+		"SCHEMA_MODE: value staging is not one of dev, prod",
+		"SCHEMA_PORT: value 70000 is greater than the maximum 65535",
+		"SCHEMA_HOST: required, but not set",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected violation %q, received: %v", expected, err)
+		}
+	}
+
+	type Partial struct {
+		Mode string `config:"MODE"`
+	}
+
+	err = sc.ValidateAgainst(&Schema{}, &Partial{Mode: "dev"})
+	if err == nil || !strings.Contains(err.Error(), "SCHEMA_PORT: no field of the live struct has this config") {
+		t.Fatalf("expected missing field violation, received: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DefaultSensitivePatterns lists config name substrings that commonly denote secrets, for use as SensitivePatterns.
+var DefaultSensitivePatterns = []string{"password", "secret", "token", "key"}
+
+// WriteOptions controls how WriteToWithOptions writes configs.
+type WriteOptions struct {
+	// Filter decides whether the field with the given config name is written. secure tells whether the value of the
+	// field is masked. When nil, all fields are written.
+	Filter func(key string, secure bool) bool
+	// MaskAll masks the values of all fields as if they were tagged with the `secure` option, e.g. to write a struct
+	// that only holds secrets.
+	MaskAll bool
+	// Export writes one shell statement per line, export NAME='VALUE', with the full config name including the
+	// prefix, so that the output can be sourced by a shell to reproduce the configuration. Values are formatted like
+	// ExportEnv does, so that ParseTo reads them back, and single-quoted, with embedded single quotes written as '\''.
+	// Computed fields are not written, since they have no config name.
+	Export bool
+}
+
+// WriteTo writes the configs of the struct pointed by obj to w as comma-separated NAME=VALUE pairs, e.g. to log the
+// configuration a service starts with. Values of fields tagged with the `secure` option, or whose config name matches
+// SensitivePatterns, are masked.
+func (sc ServiceConfig) WriteTo(obj interface{}, w io.Writer) error {
+	return sc.WriteToWithOptions(obj, w, WriteOptions{})
+}
+
+// WriteToWithOptions writes configs like WriteTo, according to opts. For example, to write only configs that are
+// safe to show outside the team:
+//
+//	sc.WriteToWithOptions(cfg, w, config.WriteOptions{
+//		Filter: func(key string, secure bool) bool { return !secure },
+//	})
+func (sc ServiceConfig) WriteToWithOptions(obj interface{}, w io.Writer, opts WriteOptions) error {
+	assertPointer(obj)
+	sc = sc.withStructSettings(obj)
+
+	configs := make([]string, 0)
+	for _, f := range configFields(obj) {
+		isSecure := opts.MaskAll || f.opts.has("secure") || sc.isSensitive(f.key())
+		if opts.Filter != nil && !opts.Filter(f.key(), isSecure) {
+			continue
+		}
+
+		if opts.Export {
+			if f.name == "" || f.name == "-" {
+				continue
+			}
+
+			value := sc.withOptions(f.opts.getOptions()).formatValue(f.value, f.opts)
+			if isSecure && value != "" {
+				value = "********"
+			}
+
+			name := sc.getConfigName(f.name)
+			if f.opts.has("chunked") {
+				name = sc.chunkKey(f.name, 1)
+			}
+
+			configs = append(configs, fmt.Sprintf("export %s=%s\n", name, shellQuote(value)))
+			continue
+		}
+
+		value := fmt.Sprintf("%v", f.value.Interface())
+		if isSecure && value != "" {
+			value = "********"
+		}
+
+		configs = append(configs, fmt.Sprintf("%s=%s", f.key(), value))
+	}
+
+	separator := ", "
+	if opts.Export {
+		separator = ""
+	}
+
+	_, err := io.WriteString(w, strings.Join(configs, separator))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// isSensitive reports whether key contains one of SensitivePatterns, ignoring case.
+func (sc ServiceConfig) isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range sc.SensitivePatterns {
+		if pattern != "" && strings.Contains(key, strings.ToLower(pattern)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// shellQuote quotes s for a POSIX shell, in single quotes, in which no character is special except the single quote
+// itself, which is written by closing the quotes, writing an escaped quote, and opening them again.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,220 @@
+package config
+
+import (
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServiceConfig_WriteToWithOptions(t *testing.T) {
+	type TestConfig struct {
+		Host     string `config:"HOST"`
+		Password string `config:"PASSWORD,secure"`
+		Ratio    string `config:"RATIO"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "WRITE",
+		ArraySeparator: " ",
+	}
+
+	cfg := &TestConfig{Host: "localhost", Password: "hunter2", Ratio: "50%"}
+
+	var b strings.Builder
+	err := sc.WriteTo(cfg, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "HOST=localhost, PASSWORD=********, RATIO=50%" {
+		t.Fatalf("unexpected output: %s", b.String())
+	}
+
+	b.Reset()
+	err = sc.WriteToWithOptions(cfg, &b, WriteOptions{
+		Filter: func(key string, secure bool) bool { return !secure },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "HOST=localhost, RATIO=50%" {
+		t.Fatalf("unexpected filtered output: %s", b.String())
+	}
+}
+
+func TestServiceConfig_WriteTo_sensitivePatterns(t *testing.T) {
+	type TestConfig struct {
+		Host      string `config:"HOST"`
+		APIKey    string `config:"API_KEY"`
+		DBPass    string `config:"DB_PASSWORD"`
+		Signature string `config:"SIGNATURE,secure"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "SENSITIVE",
+		ArraySeparator: " ",
+	}
+
+	cfg := &TestConfig{Host: "localhost", APIKey: "abc", DBPass: "hunter2", Signature: "xyz"}
+
+	var b strings.Builder
+	err := sc.WriteTo(cfg, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "HOST=localhost, API_KEY=abc, DB_PASSWORD=hunter2, SIGNATURE=********" {
+		t.Fatalf("expected only secure fields to be masked without patterns, received: %s", b.String())
+	}
+
+	sc.SensitivePatterns = DefaultSensitivePatterns
+	b.Reset()
+	err = sc.WriteTo(cfg, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "HOST=localhost, API_KEY=********, DB_PASSWORD=********, SIGNATURE=********" {
+		t.Fatalf("unexpected output: %s", b.String())
+	}
+
+	sc.SensitivePatterns = []string{"Pass"}
+	b.Reset()
+	err = sc.WriteTo(cfg, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "HOST=localhost, API_KEY=abc, DB_PASSWORD=********, SIGNATURE=********" {
+		t.Fatalf("unexpected output with a custom pattern: %s", b.String())
+	}
+}
+
+func TestServiceConfig_WriteToWithOptions_maskAll(t *testing.T) {
+	type TestConfig struct {
+		Token  string `config:"TOKEN"`
+		Secret string `config:"SECRET"`
+		Empty  string `config:"EMPTY"`
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "MASK",
+		ArraySeparator: " ",
+	}
+
+	var b strings.Builder
+	err := sc.WriteToWithOptions(&TestConfig{Token: "abc", Secret: "def"}, &b, WriteOptions{MaskAll: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "TOKEN=********, SECRET=********, EMPTY=" {
+		t.Fatalf("unexpected output: %s", b.String())
+	}
+}
+
+func TestServiceConfig_WriteToWithOptions_export(t *testing.T) {
+	sc := ServiceConfig{
+		Prefix:         "EXPORT",
+		ArraySeparator: " ",
+	}
+
+	type TestConfig struct {
+		Greeting string `config:"GREETING"`
+		Path     string `config:"PATH"`
+		Password string `config:"PASSWORD,secure"`
+		Port     int    `config:"PORT"`
+		URL      string `config:"-,compute=http://{PORT}"`
+	}
+
+	n := &TestConfig{
+		Greeting: `it's a "test" $HOME`,
+		Path:     "a b\\c\nd",
+		Password: "hunter2",
+		Port:     8080,
+		URL:      "http://8080",
+	}
+
+	var b strings.Builder
+	err := sc.WriteToWithOptions(n, &b, WriteOptions{Export: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "export EXPORT_GREETING='it'\\''s a \"test\" $HOME'\n" +
+		"export EXPORT_PATH='a b\\c\nd'\n" +
+		"export EXPORT_PASSWORD='********'\n" +
+		"export EXPORT_PORT='8080'\n"
+	if b.String() != expected {
+		t.Fatalf("unexpected output, received: %q, expected: %q", b.String(), expected)
+	}
+
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not available to check the quoting")
+	}
+
+	out, err := exec.Command(sh, "-c", b.String()+`printf '%s|%s' "$EXPORT_GREETING" "$EXPORT_PATH"`).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != n.Greeting+"|"+n.Path {
+		t.Fatalf("unexpected values after sourcing, received: %q", out)
+	}
+}
+
+func TestServiceConfig_WriteToWithOptions_exportRoundTrip(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not available to source the output")
+	}
+
+	sc := ServiceConfig{
+		Prefix:         "EXPORTRT",
+		ArraySeparator: " ",
+	}
+
+	type TestConfig struct {
+		List    []string          `config:"LIST"`
+		Times   []time.Time       `config:"TIMES"`
+		Ports   []int             `config:"PORTS"`
+		Headers map[string]string `config:"HEADERS"`
+		Cert    string            `config:"CERT,chunked"`
+		Limits  map[string]int    `config:"LIMITS,json"`
+	}
+
+	cfg := &TestConfig{
+		List:    []string{"x", "y"},
+		Times:   []time.Time{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)},
+		Ports:   []int{80, 443},
+		Headers: map[string]string{"a": "1", "b": "it's"},
+		Cert:    "-----BEGIN CERTIFICATE-----",
+		Limits:  map[string]int{"a": 1},
+	}
+
+	var b strings.Builder
+	err = sc.WriteToWithOptions(cfg, &b, WriteOptions{Export: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := exec.Command(sh, "-c", b.String()+"env").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := MapSource{}
+	for _, line := range strings.Split(string(out), "\n") {
+		key, value, _ := strings.Cut(line, "=")
+		if strings.HasPrefix(key, "EXPORTRT_") {
+			env[key] = value
+		}
+	}
+
+	sc.Sources = []Source{env}
+	n := &TestConfig{}
+	err = sc.ParseTo(n)
+	if err != nil {
+		t.Fatalf("cannot parse %v: %v", env, err)
+	}
+	if !reflect.DeepEqual(n, cfg) {
+		t.Fatalf("decoded config is not the same with expectation, received: %v, expected: %v", n, cfg)
+	}
+}